@@ -0,0 +1,73 @@
+package main
+
+import "time"
+
+type Address struct {
+	CEP          string `json:"cep"`
+	Street       string `json:"street,omitempty"`
+	Complement   string `json:"complement,omitempty"`
+	Neighborhood string `json:"neighborhood,omitempty"`
+	City         string `json:"city,omitempty"`
+	State        string `json:"state,omitempty"`
+	Country      string `json:"country,omitempty"`
+	DDD          string `json:"ddd,omitempty"`
+	// IBGE, SIAFI e GIA são os códigos do município no IBGE e no SIAFI
+	// (Tesouro) e o da GIA (ICMS de SP), quando o provider os informa.
+	IBGE     string    `json:"ibge,omitempty"`
+	SIAFI    string    `json:"siafi,omitempty"`
+	GIA      string    `json:"gia,omitempty"`
+	Location *Location `json:"location,omitempty"`
+}
+
+// empty informa se a não localiza nada: sem logradouro, bairro, cidade nem
+// coordenadas. Só o CEP de volta, ou o país, não bastam para um resultado.
+func (a Address) empty() bool {
+	return a.Street == "" && a.Neighborhood == "" && a.City == "" && a.Location == nil
+}
+
+// Location guarda as coordenadas do CEP, quando o provider as informa.
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Altitude  float64 `json:"altitude,omitempty"`
+
+	DisplayName string `json:"display_name,omitempty"`
+}
+
+// addressFieldNames lista, em ordem de exibição, os campos de texto do
+// Address acessíveis por nome via addressFields.
+var addressFieldNames = []string{"cep", "street", "complement", "neighborhood", "city", "state", "country", "ddd", "ibge", "siafi", "gia"}
+
+var addressFields = map[string]func(*Address) *string{
+	"cep":          func(a *Address) *string { return &a.CEP },
+	"street":       func(a *Address) *string { return &a.Street },
+	"complement":   func(a *Address) *string { return &a.Complement },
+	"neighborhood": func(a *Address) *string { return &a.Neighborhood },
+	"city":         func(a *Address) *string { return &a.City },
+	"state":        func(a *Address) *string { return &a.State },
+	"country":      func(a *Address) *string { return &a.Country },
+	"ddd":          func(a *Address) *string { return &a.DDD },
+	"ibge":         func(a *Address) *string { return &a.IBGE },
+	"siafi":        func(a *Address) *string { return &a.SIAFI },
+	"gia":          func(a *Address) *string { return &a.GIA },
+}
+
+type APIResult struct {
+	Addr   Address
+	Source string
+	Err    error
+
+	// Provenance indica, no modo merge, de qual provider veio cada campo.
+	Provenance map[string]string
+	// CacheHit indica que o endereço veio do cache, sem consultar a rede.
+	CacheHit bool
+	// Stale indica um endereço vindo do cache já expirado, usado porque
+	// todos os providers falharam.
+	Stale bool
+	// Latency é quanto o provider levou para responder; zero para respostas
+	// do cache e combinadas de vários providers.
+	Latency time.Duration
+	// Confidence é, na busca por texto livre, a chance de 0 a 1 de este ser
+	// o endereço procurado; zero nas outras consultas.
+	Confidence float64
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// options são as opções de linha de comando que montam o Resolver,
+// compartilhadas pelos subcomandos que fazem consultas.
+type options struct {
+	providers        string
+	timeout          time.Duration
+	config           string
+	country          string
+	strategy         string
+	hedgeDelay       time.Duration
+	quorum           int
+	prefer           string
+	preferGrace      time.Duration
+	stepTimeout      time.Duration
+	retries          int
+	retryDelay       time.Duration
+	retryBudget      int
+	providerTimeouts string
+	adaptive         bool
+	cacheSize        int
+	cacheTTL         time.Duration
+	softTTL          time.Duration
+	negativeTTL      time.Duration
+	noCache          bool
+	merge            bool
+	pluginDir        string
+	debug            bool
+	geo              bool
+	geocoder         string
+	streetType       string
+	normalize        string
+	dryRun           bool
+}
+
+// registerOptions declara em fs as opções que montam o Resolver.
+func registerOptions(fs *flag.FlagSet) *options {
+	o := &options{}
+	fs.StringVar(&o.providers, "providers", "", "providers separados por vírgula (padrão: todos)")
+	fs.DurationVar(&o.timeout, "timeout", 0, "prazo total da consulta (padrão: o maior timeout por provider, ou 1s)")
+	fs.StringVar(&o.config, "config", "", "arquivo de configuração")
+	// O caminho padrão só aparece no -h; vazio continua querendo dizer "não informado"
+	fs.Lookup("config").DefValue = defaultConfigPath()
+	fs.StringVar(&o.country, "country", "BR", "país do código postal (ISO 3166-1 alfa-2)")
+	fs.StringVar(&o.strategy, "strategy", "race", "estratégia de consulta: "+strings.Join(strategyNames(), ", "))
+	fs.DurationVar(&o.hedgeDelay, "hedge-delay", 200*time.Millisecond, "espera antes de acionar o próximo provider na estratégia hedge")
+	fs.IntVar(&o.quorum, "quorum", 0, "mínimo de providers que devem concordar (implica --strategy quorum)")
+	fs.StringVar(&o.prefer, "prefer", "", "provider preferido; sua resposta é usada se chegar dentro de --prefer-grace (implica --strategy prefer)")
+	fs.DurationVar(&o.preferGrace, "prefer-grace", 300*time.Millisecond, "janela de espera pelo provider de --prefer")
+	fs.DurationVar(&o.stepTimeout, "step-timeout", 0, "prazo de cada provider na estratégia fallback (padrão: divide o timeout entre eles)")
+	fs.IntVar(&o.retries, "retries", 1, "novas tentativas por provider em falhas transitórias de rede e respostas 5xx")
+	fs.DurationVar(&o.retryDelay, "retry-delay", 100*time.Millisecond, "espera base entre tentativas (dobra a cada uma)")
+	fs.IntVar(&o.retryBudget, "retry-budget", 0, "máximo de novas tentativas somando todos os providers e CEPs (0 = sem limite)")
+	fs.StringVar(&o.providerTimeouts, "provider-timeout", "", "timeout por provider, ex.: BrasilAPI=700ms,ViaCEP=1s")
+	fs.BoolVar(&o.adaptive, "adaptive-timeout", false, "deriva o timeout de cada provider do p95 das latências recentes")
+	fs.IntVar(&o.cacheSize, "cache-size", 1000, "máximo de CEPs no cache em memória")
+	fs.DurationVar(&o.cacheTTL, "cache-ttl", 24*time.Hour, "validade dos endereços em cache")
+	fs.DurationVar(&o.softTTL, "cache-soft-ttl", 0, "idade a partir da qual o endereço em cache é atualizado em segundo plano (0 desliga)")
+	fs.DurationVar(&o.negativeTTL, "negative-cache-ttl", time.Hour, "validade em cache dos CEPs não encontrados (0 desliga)")
+	fs.BoolVar(&o.noCache, "no-cache", false, "não usa o cache (nem em memória nem em disco)")
+	fs.BoolVar(&o.merge, "merge", false, "atalho para --strategy merge")
+	fs.StringVar(&o.pluginDir, "plugin-dir", "", "diretório com plugins Go (.so) de providers")
+	fs.Func("lang", "idioma das mensagens: pt ou en (padrão: do ambiente)", setLang)
+	fs.BoolVar(&noColor, "no-color", noColor, "não colore a saída (o mesmo que definir NO_COLOR)")
+	fs.BoolVar(&o.geo, "geo", false, "completa as coordenadas dos endereços com um geocoder; falhas só geram avisos")
+	fs.StringVar(&o.geocoder, "geocoder", "", "geocoder do --geo: google ou nominatim (padrão: os configurados, ou nominatim)")
+	fs.StringVar(&o.streetType, "street-type", "", "padroniza o tipo de logradouro: expand (Av. → Avenida) ou abbrev (Avenida → Av.)")
+	fs.StringVar(&o.normalize, "normalize", "", "transforma os textos do endereço: ascii (sem acentos), upper ou title; combine com vírgulas, ex.: ascii,upper")
+	fs.BoolVar(&o.dryRun, "dry-run", false, "mostra as requisições que seriam feitas, na ordem dos providers, sem acessar a rede")
+	fs.BoolVar(&o.debug, "debug", false, "mostra na saída de erro a resposta e a latência de cada provider, inclusive dos que perdem a corrida")
+	fs.BoolVar(&o.debug, "v", false, "atalho para --debug")
+	return o
+}
+
+// app reúne o que foi montado a partir das opções.
+type app struct {
+	cfg      Config
+	resolver *Resolver
+	// cache é o cache persistente (disco, Redis ou SQLite), sem a camada
+	// em memória; nil com --no-cache.
+	cache   Cache
+	closers []func()
+	// strategy e plan descrevem a consulta montada, para o --dry-run.
+	strategy string
+	plan     []providerPlan
+}
+
+// Close espera as atualizações do cache em segundo plano e libera os
+// recursos abertos por setup.
+func (a *app) Close() {
+	if a.resolver != nil {
+		a.resolver.Wait()
+	}
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		a.closers[i]()
+	}
+}
+
+// loadConfig carrega o arquivo de configuração e registra os providers que
+// ele declara.
+func (o *options) loadConfig() (Config, error) {
+	path := o.config
+	if path == "" {
+		path = defaultConfigPath()
+	}
+	cfg, err := loadConfig(path, o.config != "")
+	if err != nil {
+		return cfg, err
+	}
+	if t := cfg.Tokens.CEPAberto; t != "" && os.Getenv("CEPABERTO_TOKEN") == "" {
+		RegisterProvider(cepAbertoProvider{token: t})
+	}
+	// Os geocoders leem as credenciais do ambiente
+	for env, value := range map[string]string{
+		"GOOGLE_MAPS_API_KEY":  cfg.Tokens.GoogleMaps,
+		"NOMINATIM_USER_AGENT": cfg.Tokens.NominatimUserAgent,
+	} {
+		if value != "" && os.Getenv(env) == "" {
+			os.Setenv(env, value)
+		}
+	}
+	for _, pc := range cfg.Providers {
+		p, err := newTemplateProvider(pc)
+		if err != nil {
+			return cfg, err
+		}
+		RegisterProvider(p)
+	}
+	for _, pc := range cfg.Plugins {
+		p, err := newExecProvider(pc)
+		if err != nil {
+			return cfg, err
+		}
+		RegisterProvider(p)
+	}
+	for _, path := range cfg.OfflineDataset {
+		if err := offline.loadFile(path); err != nil {
+			return cfg, err
+		}
+	}
+	if o.pluginDir != "" {
+		plugins, err := loadPlugins(o.pluginDir)
+		if err != nil {
+			return cfg, err
+		}
+		for _, p := range plugins {
+			RegisterProvider(p)
+		}
+	}
+	return cfg, nil
+}
+
+// openCache abre o cache persistente escolhido na configuração.
+func (a *app) openCache() error {
+	if addr := os.Getenv("CEP_REDIS_ADDR"); addr != "" {
+		a.cfg.Cache.Redis.Addr = addr
+	}
+	switch {
+	case a.cfg.Cache.Plugin != "":
+		c, err := loadCachePlugin(a.cfg.Cache.Plugin)
+		if err != nil {
+			return err
+		}
+		a.cache = c
+	case a.cfg.Cache.Redis.Addr != "":
+		a.cache = newRedisCache(a.cfg.Cache.Redis)
+	case a.cfg.Cache.SQLite.Path != "":
+		db, err := openSQLiteCache(a.cfg.Cache.SQLite)
+		if err != nil {
+			return fmt.Errorf("cache sqlite: %w", err)
+		}
+		a.closers = append(a.closers, func() { db.Close() })
+		a.cache = db
+	case defaultCacheDir() != "":
+		a.cache = diskCache{dir: defaultCacheDir()}
+	}
+	return nil
+}
+
+// setup carrega a configuração e monta o Resolver.
+func (o *options) setup() (*app, error) {
+	cfg, err := o.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	a := &app{cfg: cfg}
+
+	var names []string
+	if o.providers != "" {
+		names = strings.Split(o.providers, ",")
+	}
+	providers, err := SelectProviders(names)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		cfg.sortByPriority(providers)
+	}
+	if !strings.EqualFold(o.country, "BR") {
+		providers = []Provider{zippopotamProvider{country: o.country}}
+	}
+	timeouts, err := parseProviderTimeouts(o.providerTimeouts)
+	if err != nil {
+		return nil, err
+	}
+	latencies := loadLatencyTracker(defaultLatencyPath())
+	a.closers = append(a.closers, func() {
+		if err := latencies.save(); err != nil {
+			fmt.Fprint(os.Stderr, tr("Aviso: não foi possível salvar as latências: %v\n", err))
+		}
+	})
+
+	// O prazo geral cobre o maior timeout por provider; quem não tem timeout
+	// próprio usa o padrão de 1 segundo
+	var timeout time.Duration
+	budget := newRetryBudget(o.retryBudget)
+	for i, p := range providers {
+		policy := RetryPolicy{Count: o.retries, BaseDelay: o.retryDelay, Jitter: 0.2}
+		if r := cfg.settingsFor(p.Name()).Retry; r != nil {
+			policy = *r
+		}
+		pt := cfg.settingsFor(p.Name()).Timeout
+		if d, ok := timeouts[strings.ToLower(p.Name())]; ok {
+			pt = d
+		}
+		if pt == 0 && o.adaptive {
+			pt = latencies.timeout(p.Name())
+		}
+		if pt > 0 {
+			timeout = max(timeout, pt)
+		} else {
+			timeout = max(timeout, time.Second)
+		}
+		a.plan = append(a.plan, providerPlan{provider: p, timeout: pt, retries: policy.Count})
+		p = withBackoff(withRateLimit(p, cfg.settingsFor(p.Name()).RateLimit))
+		p = withLatency(withRetry(p, policy, budget), latencies)
+		providers[i] = withTimeout(withBreaker(p, cfg.breakerFor(p.Name())), pt)
+	}
+	if o.timeout > 0 {
+		timeout = o.timeout
+	}
+	if o.debug {
+		pending := enableDebug(providers)
+		a.closers = append(a.closers, pending.Wait)
+	}
+
+	name := o.strategy
+	if o.merge {
+		name = "merge"
+	}
+	if o.quorum > 0 {
+		name = "quorum"
+	}
+	if o.prefer != "" {
+		name = "prefer"
+		if _, err := SelectProviders([]string{o.prefer}); err != nil {
+			return nil, err
+		}
+	}
+	strategy, ok := strategies[name]
+	if !ok {
+		return nil, errors.New(tr("estratégia desconhecida: %s (use %s)", name, strings.Join(strategyNames(), ", ")))
+	}
+	a.strategy = name
+
+	a.resolver = &Resolver{
+		Providers: providers,
+		Strategy:  strategy,
+		Options: StrategyOptions{
+			HedgeDelay:  o.hedgeDelay,
+			Weights:     cfg.weights(providers),
+			Quorum:      o.quorum,
+			Prefer:      o.prefer,
+			PreferGrace: o.preferGrace,
+			StepTimeout: o.stepTimeout,
+		},
+		Timeout:     timeout,
+		Country:     o.country,
+		CacheTTL:    o.cacheTTL,
+		NegativeTTL: o.negativeTTL,
+		SoftTTL:     o.softTTL,
+	}
+	if o.geo {
+		if a.resolver.Geocoders, err = selectGeocoders(o.geocoder); err != nil {
+			return nil, err
+		}
+	}
+	if o.streetType != "" {
+		t, err := streetTypeTransform(o.streetType)
+		if err != nil {
+			return nil, err
+		}
+		a.resolver.Transforms = append(a.resolver.Transforms, t)
+	}
+	if o.normalize != "" {
+		t, err := normalizeTransform(o.normalize)
+		if err != nil {
+			return nil, err
+		}
+		a.resolver.Transforms = append(a.resolver.Transforms, t)
+	}
+	if strings.EqualFold(o.country, "BR") {
+		a.resolver.Normalize = normalizeCEP
+		a.resolver.Validate = validateCEP
+	}
+	if !o.noCache {
+		if err := a.openCache(); err != nil {
+			a.Close()
+			return nil, err
+		}
+		caches := tieredCache{newMemoryCache(o.cacheSize)}
+		if a.cache != nil {
+			caches = append(caches, a.cache)
+		}
+		a.resolver.Cache = caches
+		a.closers = append(a.closers, func() {
+			if err := savePersistedStats(defaultStatsPath(), cacheLookupsTotal.snapshot()); err != nil {
+				fmt.Fprint(os.Stderr, tr("Aviso: não foi possível salvar as estatísticas do cache: %v\n", err))
+			}
+		})
+	}
+	return a, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// runBench consulta o mesmo CEP várias vezes, sem cache, e resume as
+// latências e qual provider venceu cada consulta.
+func runBench(args []string) int {
+	fs := flag.NewFlagSet("cep bench", flag.ExitOnError)
+	opts, n := registerBenchFlags(fs)
+	parseFlags(fs, args)
+
+	if fs.NArg() != 1 || *n < 1 {
+		fmt.Println(tr("Uso: go run main.go bench [opções] [-n 20] <cep>"))
+		return exitUsage
+	}
+	cep := fs.Arg(0)
+	if !checkCEPs(os.Stdout, opts.country, []string{cep}) {
+		return exitInvalidCEP
+	}
+
+	opts.noCache = true
+	app, err := opts.setup()
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	defer app.Close()
+
+	var (
+		elapsed []time.Duration
+		wins    = map[string]int{}
+		failed  int
+	)
+	for range *n {
+		start := time.Now()
+		lookup := app.resolver.Resolve(context.Background(), cep)
+		elapsed = append(elapsed, time.Since(start))
+		if !lookup.OK() {
+			failed++
+			continue
+		}
+		for _, res := range lookup.Results {
+			if res.Err == nil {
+				wins[res.Source]++
+			}
+		}
+	}
+
+	sort.Slice(elapsed, func(i, j int) bool { return elapsed[i] < elapsed[j] })
+	var total time.Duration
+	for _, d := range elapsed {
+		total += d
+	}
+	fmt.Print(tr("Consultas: %d (%d falharam)\n", *n, failed))
+	fmt.Print(tr("Latência: mín %s, média %s, p50 %s, p95 %s, máx %s\n",
+		elapsed[0].Round(time.Millisecond),
+		(total / time.Duration(len(elapsed))).Round(time.Millisecond),
+		percentile(elapsed, 0.50).Round(time.Millisecond),
+		percentile(elapsed, 0.95).Round(time.Millisecond),
+		elapsed[len(elapsed)-1].Round(time.Millisecond),
+	))
+
+	names := make([]string, 0, len(wins))
+	for name := range wins {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if wins[names[i]] != wins[names[j]] {
+			return wins[names[i]] > wins[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	for _, name := range names {
+		fmt.Print(tr("  %s: %d vitória(s)\n", name, wins[name]))
+	}
+	return exitOK
+}
+
+func registerBenchFlags(fs *flag.FlagSet) (*options, *int) {
+	return registerOptions(fs), fs.Int("n", 20, "número de consultas")
+}
+
+// percentile devolve o percentil p (0 a 1) de sorted, já ordenado.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	return sorted[int(p*float64(len(sorted)-1))]
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+var errCircuitOpen error = localizedError("circuito aberto")
+
+// BreakerSettings configura o circuit breaker de um provider. No arquivo de
+// configuração, vale para todos os providers (circuit_breaker) ou para um
+// específico (provider_settings.<nome>.circuit_breaker):
+//
+//	circuit_breaker:
+//	  failures: 5
+//	  window: 30s
+//	  cooldown: 30s
+type BreakerSettings struct {
+	// Failures é o número de falhas consecutivas, dentro de Window, que abre
+	// o circuito. Zero desliga o circuit breaker.
+	Failures int           `yaml:"failures"`
+	Window   time.Duration `yaml:"window"`
+	// Cooldown é quanto o circuito fica aberto antes de deixar passar uma
+	// chamada de teste (meio-aberto).
+	Cooldown time.Duration `yaml:"cooldown"`
+}
+
+var defaultBreakerSettings = BreakerSettings{Failures: 5, Window: 30 * time.Second, Cooldown: 30 * time.Second}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+type circuitBreaker struct {
+	settings BreakerSettings
+
+	mu           sync.Mutex
+	state        breakerState
+	failures     int
+	firstFailure time.Time
+	openedAt     time.Time
+}
+
+// allow informa se uma chamada pode ser feita agora.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.settings.Cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		// Já há uma chamada de teste em andamento
+		return false
+	default:
+		return true
+	}
+}
+
+// record registra o resultado de uma chamada permitida por allow.
+func (b *circuitBreaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if !failed {
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+		b.openedAt = now
+		return
+	}
+
+	if b.failures == 0 || now.Sub(b.firstFailure) > b.settings.Window {
+		b.failures = 0
+		b.firstFailure = now
+	}
+	b.failures++
+	if b.failures >= b.settings.Failures {
+		b.state = breakerOpen
+		b.openedAt = now
+	}
+}
+
+// release devolve uma chamada permitida por allow que terminou sem dizer nada
+// sobre o provider (cancelada). Se era a chamada de teste, o circuito volta a
+// ficar aberto, e a próxima chamada faz um novo teste.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+	}
+}
+
+// breakerFailure informa se err indica um provider com problemas: falhas de
+// rede, respostas 5xx e tempo esgotado. "CEP não encontrado", "CEP inválido"
+// e outras respostas 4xx mostram que o provider está respondendo.
+func breakerFailure(err error) bool {
+	if err == nil || errors.Is(err, ErrNotFound) || errors.Is(err, ErrInvalidCEP) {
+		return false
+	}
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code >= 500
+	}
+	if errors.Is(err, ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+type breakerProvider struct {
+	Provider
+	breaker *circuitBreaker
+}
+
+// withBreaker envolve p com um circuit breaker configurado por settings.
+func withBreaker(p Provider, settings BreakerSettings) Provider {
+	if settings.Failures <= 0 {
+		return p
+	}
+	return breakerProvider{Provider: p, breaker: &circuitBreaker{settings: settings}}
+}
+
+func (b breakerProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	if !b.breaker.allow() {
+		return Address{}, fmt.Errorf("%s: %w", b.Name(), errCircuitOpen)
+	}
+
+	addr, err := b.Provider.Fetch(ctx, cep)
+	// Cancelamento por outro provider ter vencido não é falha deste
+	if errors.Is(err, context.Canceled) {
+		b.breaker.release()
+	} else {
+		b.breaker.record(breakerFailure(err))
+	}
+	return addr, err
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestBreakerFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"sucesso", nil, false},
+		{"não encontrado", fmt.Errorf("ViaCEP: %w", ErrNotFound), false},
+		{"CEP inválido", fmt.Errorf("%w: %w", ErrInvalidCEP, &HTTPStatusError{Code: 400}), false},
+		{"429", &HTTPStatusError{Code: 429}, false},
+		{"500", &HTTPStatusError{Code: 500}, true},
+		{"503", fmt.Errorf("BrasilAPI: %w", &HTTPStatusError{Code: 503}), true},
+		{"tempo esgotado", ctxError(timedOut()), true},
+		{"rede", &url.Error{Op: "Get", URL: "http://x", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}, true},
+		{"cancelado", context.Canceled, false},
+		{"outro", errors.New("resposta inesperada"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := breakerFailure(tt.err); got != tt.want {
+				t.Errorf("breakerFailure(%v) = %v, quero %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func timedOut() context.Context {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now())
+	cancel()
+	return ctx
+}
+
+func TestCircuitBreakerStates(t *testing.T) {
+	b := &circuitBreaker{settings: BreakerSettings{Failures: 2, Window: time.Minute, Cooldown: 20 * time.Millisecond}}
+
+	steps := []struct {
+		name  string
+		do    func()
+		state breakerState
+		allow bool
+	}{
+		{"começa fechado", func() {}, breakerClosed, true},
+		{"uma falha não abre", func() { b.record(true) }, breakerClosed, true},
+		{"sucesso zera as falhas", func() { b.record(false) }, breakerClosed, true},
+		{"abre com Failures falhas", func() { b.record(true); b.record(true) }, breakerOpen, false},
+		{"meio-aberto depois do cooldown", func() { time.Sleep(30 * time.Millisecond) }, breakerOpen, true},
+		{"falha no teste reabre", func() { b.record(true) }, breakerOpen, false},
+		{"teste cancelado volta a abrir", func() { time.Sleep(30 * time.Millisecond); b.allow(); b.release() }, breakerOpen, true},
+		{"sucesso no teste fecha", func() { b.record(false) }, breakerClosed, true},
+	}
+	for _, s := range steps {
+		s.do()
+		if b.state != s.state {
+			t.Fatalf("%s: estado %v, quero %v", s.name, b.state, s.state)
+		}
+		if s.allow {
+			if !b.allow() {
+				t.Fatalf("%s: allow() = false", s.name)
+			}
+		} else if b.allow() {
+			t.Fatalf("%s: allow() = true", s.name)
+		}
+	}
+}
+
+func TestBreakerProviderCanceledTrial(t *testing.T) {
+	p := withBreaker(stubProvider{name: "Lento", err: errors.New("x"), delay: time.Hour},
+		BreakerSettings{Failures: 1, Window: time.Minute, Cooldown: time.Millisecond}).(breakerProvider)
+	p.breaker.record(true)
+	time.Sleep(2 * time.Millisecond)
+
+	// A chamada de teste perde a corrida e é cancelada
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := p.Fetch(ctx, "01001000"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, quero context.Canceled", err)
+	}
+	if !p.breaker.allow() {
+		t.Fatal("o circuito ficou meio-aberto para sempre depois de um teste cancelado")
+	}
+}
+
+func TestBreakerProviderNotFoundIsNotFailure(t *testing.T) {
+	p := withBreaker(stubProvider{name: "ViaCEP", err: ErrNotFound},
+		BreakerSettings{Failures: 2, Window: time.Minute, Cooldown: time.Minute})
+	for range 5 {
+		if _, err := p.Fetch(context.Background(), "99999999"); errors.Is(err, errCircuitOpen) {
+			t.Fatal("CEPs inexistentes abriram o circuito")
+		}
+	}
+}
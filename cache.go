@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// CacheEntry é um endereço guardado em cache.
+type CacheEntry struct {
+	Addr      Address   `json:"address"`
+	Source    string    `json:"source"`
+	StoredAt  time.Time `json:"stored_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// NotFound marca um resultado negativo: os providers disseram que o CEP
+	// não existe.
+	NotFound bool `json:"not_found,omitempty"`
+}
+
+// Expired informa se a entrada já passou do TTL.
+func (e CacheEntry) Expired() bool {
+	return !e.ExpiresAt.IsZero() && time.Now().After(e.ExpiresAt)
+}
+
+// Cache guarda os endereços resolvidos, por CEP. Get devolve também entradas
+// expiradas, para que possam ser servidas como desatualizadas quando todos
+// os providers falharem; cabe a quem chama verificar Expired.
+//
+// Set recebe o TTL da entrada (zero para sem expiração), já refletido em
+// entry.ExpiresAt, para backends que expiram chaves sozinhos. Um backend pode
+// manter a entrada além do TTL para servi-la como desatualizada. Delete remove
+// a entrada, se existir.
+type Cache interface {
+	Get(cep string) (CacheEntry, bool)
+	Set(cep string, entry CacheEntry, ttl time.Duration)
+	Delete(cep string)
+}
+
+// remainingTTL é o TTL que resta a e, para regravá-la em outro cache.
+// Entradas já expiradas recebem um TTL mínimo em vez de zero, que significaria
+// nunca expirar.
+func remainingTTL(e CacheEntry) time.Duration {
+	if e.ExpiresAt.IsZero() {
+		return 0
+	}
+	return max(time.Until(e.ExpiresAt), time.Second)
+}
+
+// cacheKey é a chave de cache do código postal cep do país country (BR se
+// vazio), como "BR-01001000" ou "GB-SW1A1AA". Do código ficam só letras, em
+// maiúsculas, e dígitos: separadores como espaço e hífen não distinguem
+// códigos, e a chave também serve de nome de arquivo. Um código sem letras
+// nem dígitos não tem chave ("").
+func cacheKey(country, cep string) string {
+	code := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, cep)
+	if code == "" {
+		return ""
+	}
+	if country == "" {
+		country = "BR"
+	}
+	return strings.ToUpper(country) + "-" + code
+}
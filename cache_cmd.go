@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// runCache implementa o subcomando cache: stats, clear e warm.
+func runCache(args []string) int {
+	fs := flag.NewFlagSet("cep cache", flag.ExitOnError)
+	opts := registerOptions(fs)
+	parseFlags(fs, args)
+
+	if fs.NArg() == 0 {
+		printUsage(os.Stdout)
+		return exitUsage
+	}
+	app, err := opts.setup()
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	defer app.Close()
+
+	switch cmd := fs.Arg(0); cmd {
+	case "stats":
+		return cacheStats(app)
+	case "clear":
+		return cacheClear(app)
+	case "warm":
+		return runCacheWarm(app, fs.Args()[1:])
+	default:
+		fmt.Print(tr("subcomando de cache desconhecido: %s\n", cmd))
+		return exitUsage
+	}
+}
+
+func cacheStats(app *app) int {
+	s := loadPersistedStats(defaultStatsPath())
+	hits, misses := s.Counts["hit"], s.Counts["miss"]
+	fmt.Print(tr("Acertos: %d\nFalhas: %d\nDesatualizados servidos: %d\n", hits, misses, s.Counts["stale"]))
+	if total := hits + misses; total > 0 {
+		fmt.Print(tr("Taxa de acerto: %.1f%%\n", float64(hits)/float64(total)*100))
+	}
+	if !s.Since.IsZero() {
+		fmt.Print(tr("Desde: %s\n", s.Since.Format("2006-01-02 15:04:05")))
+	}
+
+	inspector, ok := app.cache.(cacheInspector)
+	if !ok {
+		return exitOK
+	}
+	st, err := inspector.Stats()
+	if err != nil {
+		fmt.Print(tr("Erro ao ler o cache: %v\n", err))
+		return exitUsage
+	}
+	fmt.Print(tr("Entradas: %d\nExpiradas: %d\nNegativas: %d\n", st.Entries, st.Expired, st.Negative))
+	return exitOK
+}
+
+func cacheClear(app *app) int {
+	clearer, ok := app.cache.(cacheClearer)
+	if !ok {
+		fmt.Println(tr("O cache configurado não pode ser limpo"))
+		return exitUsage
+	}
+	if err := clearer.Clear(); err != nil {
+		fmt.Print(tr("Erro ao limpar o cache: %v\n", err))
+		return exitUsage
+	}
+	if path := defaultStatsPath(); path != "" {
+		os.Remove(path)
+	}
+	fmt.Println(tr("Cache limpo"))
+	return exitOK
+}
+
+func runCacheWarm(app *app, args []string) int {
+	fs := flag.NewFlagSet("cep cache warm", flag.ExitOnError)
+	concurrency := fs.Int("concurrency", 4, "número máximo de consultas simultâneas")
+	rate := fs.Float64("rate", 5, "máximo de consultas por segundo (0 desativa o limite)")
+	parseFlags(fs, args)
+
+	if fs.NArg() != 1 {
+		fmt.Println(tr("Uso: go run main.go cache warm [--concurrency 4] [--rate 5] <arquivo>"))
+		return exitUsage
+	}
+	return cacheWarm(app, fs.Arg(0), max(*concurrency, 1), *rate)
+}
+
+// cacheWarm consulta cada CEP do arquivo em path (um por linha), gravando os
+// resultados no cache. No máximo concurrency consultas rodam ao mesmo tempo, a
+// no máximo rate por segundo.
+func cacheWarm(app *app, path string, concurrency int, rate float64) int {
+	if app.resolver.Cache == nil {
+		fmt.Println(tr("Não há cache para aquecer (--no-cache)"))
+		return exitUsage
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	defer f.Close()
+
+	ceps, err := readCEPs(f)
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+
+	var bucket *tokenBucket
+	if rate > 0 {
+		bucket = newTokenBucket(RateLimit{Rate: rate, Burst: concurrency})
+	}
+
+	jobs := make(chan string)
+	var (
+		mu                     sync.Mutex
+		wg                     sync.WaitGroup
+		warmed, cached, failed int
+	)
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for cep := range jobs {
+				lookup := app.resolver.Resolve(context.Background(), cep)
+				mu.Lock()
+				switch {
+				case !lookup.OK():
+					failed++
+					fmt.Fprint(os.Stderr, tr("Falha ao aquecer %s: %v\n", cep, lookup.Results[0].Err))
+				case lookup.Results[0].CacheHit:
+					cached++
+				default:
+					warmed++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, cep := range ceps {
+		if bucket != nil {
+			bucket.wait(context.Background())
+		}
+		jobs <- cep
+	}
+	close(jobs)
+	wg.Wait()
+
+	fmt.Print(tr("%d CEP(s) aquecidos, %d já estavam em cache, %d falharam\n", warmed, cached, failed))
+	if failed > 0 {
+		return exitProvidersError
+	}
+	return exitOK
+}
+
+// readCEPs lê um CEP por linha de r, ignorando linhas vazias e comentários
+// iniciados por #.
+func readCEPs(r io.Reader) ([]string, error) {
+	var ceps []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ceps = append(ceps, line)
+	}
+	return ceps, sc.Err()
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCacheWarm(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ceps.txt")
+	if err := os.WriteFile(path, []byte("# lista\n01001000\n\n01310100\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	warmApp := func(p Provider, cache Cache) *app {
+		return &app{resolver: &Resolver{
+			Providers: []Provider{p},
+			Strategy:  raceStrategy,
+			Timeout:   time.Second,
+			Cache:     cache,
+			CacheTTL:  time.Hour,
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		app      *app
+		wantCode int
+	}{
+		{"todos aquecidos", warmApp(okProvider("A", addrSe), newMemoryCache(10)), exitOK},
+		{"com falhas", warmApp(failProvider("A", ErrNotFound), newMemoryCache(10)), exitProvidersError},
+		{"sem cache", warmApp(okProvider("A", addrSe), nil), exitUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cacheWarm(tt.app, path, 2, 0); got != tt.wantCode {
+				t.Errorf("código = %d, quero %d", got, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// diskCache guarda cada CEP num arquivo JSON em dir, para que execuções
+// diferentes do programa compartilhem as consultas.
+type diskCache struct {
+	dir string
+}
+
+// defaultCacheDir retorna ~/.cache/cep/addresses (ou equivalente do SO).
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "cep", "addresses")
+}
+
+func (c diskCache) path(cep string) string {
+	return filepath.Join(c.dir, cep+".json")
+}
+
+func (c diskCache) Get(cep string) (CacheEntry, bool) {
+	data, err := os.ReadFile(c.path(cep))
+	if err != nil {
+		return CacheEntry{}, false
+	}
+	var e CacheEntry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return CacheEntry{}, false
+	}
+	return e, true
+}
+
+// Set grava a entrada; falhas de escrita são ignoradas, já que o cache é só
+// uma otimização.
+func (c diskCache) Set(cep string, entry CacheEntry, _ time.Duration) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return
+	}
+	// Grava num arquivo temporário e renomeia, para que leitores
+	// concorrentes nunca vejam um JSON pela metade
+	tmp, err := os.CreateTemp(c.dir, cep+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, werr := tmp.Write(data)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil || os.Rename(tmp.Name(), c.path(cep)) != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+func (c diskCache) Delete(cep string) {
+	os.Remove(c.path(cep))
+}
+
+func (c diskCache) Stats() (CacheStats, error) {
+	var s CacheStats
+	paths, err := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	if err != nil {
+		return s, err
+	}
+	for _, path := range paths {
+		if e, ok := c.Get(strings.TrimSuffix(filepath.Base(path), ".json")); ok {
+			s.count(e)
+		}
+	}
+	return s, nil
+}
+
+func (c diskCache) Clear() error {
+	err := os.RemoveAll(c.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// tieredCache consulta as camadas em ordem (a mais rápida primeiro) e grava
+// em todas. Um acerto numa camada mais lenta é copiado para as anteriores.
+type tieredCache []Cache
+
+func (t tieredCache) Get(cep string) (CacheEntry, bool) {
+	for i, c := range t {
+		if e, ok := c.Get(cep); ok {
+			for _, upper := range t[:i] {
+				upper.Set(cep, e, remainingTTL(e))
+			}
+			return e, true
+		}
+	}
+	return CacheEntry{}, false
+}
+
+func (t tieredCache) Set(cep string, entry CacheEntry, ttl time.Duration) {
+	for _, c := range t {
+		c.Set(cep, entry, ttl)
+	}
+}
+
+func (t tieredCache) Delete(cep string) {
+	for _, c := range t {
+		c.Delete(cep)
+	}
+}
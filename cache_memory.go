@@ -0,0 +1,86 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// memoryCache é um cache LRU em memória, limitado a size entradas. Entradas
+// expiradas ficam guardadas até serem removidas pelo LRU, para que possam
+// ser servidas como desatualizadas.
+type memoryCache struct {
+	size int
+
+	mu    sync.Mutex
+	order *list.List // mais recente na frente
+	items map[string]*list.Element
+}
+
+type memoryItem struct {
+	key   string
+	entry CacheEntry
+}
+
+func newMemoryCache(size int) *memoryCache {
+	return &memoryCache{size: max(size, 1), order: list.New(), items: map[string]*list.Element{}}
+}
+
+func (c *memoryCache) Get(cep string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[cep]
+	if !ok {
+		return CacheEntry{}, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*memoryItem).entry, true
+}
+
+func (c *memoryCache) Set(cep string, entry CacheEntry, _ time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[cep]; ok {
+		el.Value.(*memoryItem).entry = entry
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[cep] = c.order.PushFront(&memoryItem{key: cep, entry: entry})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*memoryItem).key)
+	}
+}
+
+func (c *memoryCache) Delete(cep string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[cep]; ok {
+		c.order.Remove(el)
+		delete(c.items, cep)
+	}
+}
+
+func (c *memoryCache) Stats() (CacheStats, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var s CacheStats
+	for _, el := range c.items {
+		s.count(el.Value.(*memoryItem).entry)
+	}
+	return s, nil
+}
+
+func (c *memoryCache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.items = map[string]*list.Element{}
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"plugin"
+	"time"
+)
+
+// pluginCacheStore é o contrato esperado do valor retornado pelo símbolo
+// NewCache de um plugin Go (.so), para guardar o cache em memcached,
+// Ristretto ou qualquer outro armazenamento. Como o plugin não pode importar
+// os tipos deste pacote main, as entradas trafegam serializadas em JSON.
+//
+// Exemplo de plugin, compilado com `go build -buildmode=plugin`:
+//
+//	package main
+//
+//	type meuCache struct{ ... }
+//
+//	func (c *meuCache) Get(key string) ([]byte, bool) { ... }
+//	func (c *meuCache) Set(key string, value []byte, ttl time.Duration) { ... }
+//	func (c *meuCache) Delete(key string) { ... }
+//
+//	func NewCache() any { return &meuCache{} }
+type pluginCacheStore interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+	Delete(key string)
+}
+
+type pluginCache struct {
+	store pluginCacheStore
+}
+
+func (c pluginCache) Get(cep string) (CacheEntry, bool) {
+	data, ok := c.store.Get(cep)
+	if !ok {
+		return CacheEntry{}, false
+	}
+	var e CacheEntry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return CacheEntry{}, false
+	}
+	return e, true
+}
+
+func (c pluginCache) Set(cep string, entry CacheEntry, ttl time.Duration) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	c.store.Set(cep, data, ttl)
+}
+
+func (c pluginCache) Delete(cep string) { c.store.Delete(cep) }
+
+// loadCachePlugin abre o plugin em path e devolve o cache criado pelo
+// símbolo NewCache.
+func loadCachePlugin(path string) (Cache, error) {
+	plug, err := plugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", tr("plugin de cache %s", path), err)
+	}
+	sym, err := plug.Lookup("NewCache")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", tr("plugin de cache %s", path), err)
+	}
+	newCache, ok := sym.(func() any)
+	if !ok {
+		return nil, errors.New(tr("plugin de cache %s: NewCache deve ter a assinatura func() any", path))
+	}
+	store, ok := newCache().(pluginCacheStore)
+	if !ok {
+		return nil, errors.New(tr("plugin de cache %s: NewCache não retornou um cache válido", path))
+	}
+	return pluginCache{store: store}, nil
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisConfig configura o cache compartilhado no Redis:
+//
+//	cache:
+//	  redis:
+//	    addr: localhost:6379
+//	    password: segredo
+//	    db: 0
+//	    prefix: "cep:"
+//	    stale_for: 168h
+//
+// O endereço também pode vir de CEP_REDIS_ADDR.
+type RedisConfig struct {
+	Addr     string `yaml:"addr"`
+	Password string `yaml:"password"`
+	DB       int    `yaml:"db"`
+	// Prefix é prefixado a todas as chaves (padrão "cep:").
+	Prefix string `yaml:"prefix"`
+	// StaleFor é quanto tempo, além do TTL, a entrada fica no Redis para ser
+	// servida como desatualizada (padrão 7 dias).
+	StaleFor time.Duration `yaml:"stale_for"`
+}
+
+// redisTimeout limita cada operação no Redis; um cache lento não pode
+// atrasar a consulta.
+const redisTimeout = 200 * time.Millisecond
+
+type redisCache struct {
+	client   *redis.Client
+	prefix   string
+	staleFor time.Duration
+}
+
+func newRedisCache(cfg RedisConfig) *redisCache {
+	c := &redisCache{
+		client: redis.NewClient(&redis.Options{
+			Addr:     cfg.Addr,
+			Password: cfg.Password,
+			DB:       cfg.DB,
+		}),
+		prefix:   cfg.Prefix,
+		staleFor: cfg.StaleFor,
+	}
+	if c.prefix == "" {
+		c.prefix = "cep:"
+	}
+	if c.staleFor == 0 {
+		c.staleFor = 7 * 24 * time.Hour
+	}
+	return c
+}
+
+func (c *redisCache) Get(cep string) (CacheEntry, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	data, err := c.client.Get(ctx, c.prefix+cep).Bytes()
+	if err != nil {
+		return CacheEntry{}, false
+	}
+	var e CacheEntry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return CacheEntry{}, false
+	}
+	return e, true
+}
+
+// Set grava a entrada; falhas são ignoradas, já que o cache é só uma
+// otimização.
+func (c *redisCache) Set(cep string, entry CacheEntry, ttl time.Duration) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	var expiration time.Duration
+	if ttl > 0 {
+		expiration = ttl + c.staleFor
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	c.client.Set(ctx, c.prefix+cep, data, expiration)
+}
+
+func (c *redisCache) Delete(cep string) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	c.client.Del(ctx, c.prefix+cep)
+}
+
+// scan percorre as chaves com o prefixo do cache.
+func (c *redisCache) scan(ctx context.Context, fn func(keys []string) error) error {
+	var cursor uint64
+	for {
+		keys, next, err := c.client.Scan(ctx, cursor, c.prefix+"*", 500).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+func (c *redisCache) Stats() (CacheStats, error) {
+	var s CacheStats
+	ctx := context.Background()
+	err := c.scan(ctx, func(keys []string) error {
+		values, err := c.client.MGet(ctx, keys...).Result()
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			data, ok := v.(string)
+			if !ok {
+				continue
+			}
+			var e CacheEntry
+			if json.Unmarshal([]byte(data), &e) == nil {
+				s.count(e)
+			}
+		}
+		return nil
+	})
+	return s, err
+}
+
+func (c *redisCache) Clear() error {
+	ctx := context.Background()
+	return c.scan(ctx, func(keys []string) error {
+		return c.client.Del(ctx, keys...).Err()
+	})
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteConfig configura o cache em SQLite, alternativa ao Redis para quem
+// quer um único arquivo local:
+//
+//	cache:
+//	  sqlite:
+//	    path: /var/lib/cep/cache.db
+//	    stale_for: 168h
+type SQLiteConfig struct {
+	Path string `yaml:"path"`
+	// StaleFor é quanto tempo, além do TTL, a entrada é mantida para ser
+	// servida como desatualizada antes de ser removida na compactação
+	// (padrão 7 dias).
+	StaleFor time.Duration `yaml:"stale_for"`
+}
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS addresses (
+	cep        TEXT PRIMARY KEY,
+	entry      TEXT NOT NULL,
+	expires_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS addresses_expires_at ON addresses (expires_at);
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);`
+
+// sqliteCompactEvery é o intervalo mínimo entre compactações automáticas.
+const sqliteCompactEvery = 24 * time.Hour
+
+// sqliteTimeout limita cada operação no banco.
+const sqliteTimeout = 500 * time.Millisecond
+
+type sqliteCache struct {
+	db       *sql.DB
+	staleFor time.Duration
+}
+
+// openSQLiteCache abre (ou cria) o banco, garante o esquema e faz a
+// compactação se a última tiver sido há mais de sqliteCompactEvery.
+func openSQLiteCache(cfg SQLiteConfig) (*sqliteCache, error) {
+	db, err := sql.Open("sqlite", cfg.Path+"?_pragma=busy_timeout(2000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	c := &sqliteCache{db: db, staleFor: cfg.StaleFor}
+	if c.staleFor == 0 {
+		c.staleFor = 7 * 24 * time.Hour
+	}
+
+	var last int64
+	_ = db.QueryRow(`SELECT value FROM meta WHERE key = 'last_compaction'`).Scan(&last)
+	if time.Since(time.Unix(last, 0)) > sqliteCompactEvery {
+		if err := c.compact(); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// compact remove as entradas expiradas há mais de staleFor e recupera o
+// espaço do arquivo.
+func (c *sqliteCache) compact() error {
+	cutoff := time.Now().Add(-c.staleFor).Unix()
+	if _, err := c.db.Exec(`DELETE FROM addresses WHERE expires_at > 0 AND expires_at < ?`, cutoff); err != nil {
+		return err
+	}
+	if _, err := c.db.Exec(`VACUUM`); err != nil {
+		return err
+	}
+	_, err := c.db.Exec(`INSERT INTO meta (key, value) VALUES ('last_compaction', ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value`, time.Now().Unix())
+	return err
+}
+
+func (c *sqliteCache) Close() error { return c.db.Close() }
+
+func (c *sqliteCache) Stats() (CacheStats, error) {
+	var s CacheStats
+	rows, err := c.db.Query(`SELECT entry FROM addresses`)
+	if err != nil {
+		return s, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return s, err
+		}
+		var e CacheEntry
+		if json.Unmarshal([]byte(data), &e) == nil {
+			s.count(e)
+		}
+	}
+	return s, rows.Err()
+}
+
+func (c *sqliteCache) Clear() error {
+	if _, err := c.db.Exec(`DELETE FROM addresses`); err != nil {
+		return err
+	}
+	_, err := c.db.Exec(`VACUUM`)
+	return err
+}
+
+func (c *sqliteCache) Get(cep string) (CacheEntry, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), sqliteTimeout)
+	defer cancel()
+
+	var data string
+	if err := c.db.QueryRowContext(ctx, `SELECT entry FROM addresses WHERE cep = ?`, cep).Scan(&data); err != nil {
+		return CacheEntry{}, false
+	}
+	var e CacheEntry
+	if err := json.Unmarshal([]byte(data), &e); err != nil {
+		return CacheEntry{}, false
+	}
+	return e, true
+}
+
+// Set grava a entrada; falhas são ignoradas, já que o cache é só uma
+// otimização.
+func (c *sqliteCache) Set(cep string, entry CacheEntry, _ time.Duration) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	var expires int64
+	if !entry.ExpiresAt.IsZero() {
+		expires = entry.ExpiresAt.Unix()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sqliteTimeout)
+	defer cancel()
+	_, _ = c.db.ExecContext(ctx, `INSERT INTO addresses (cep, entry, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT (cep) DO UPDATE SET entry = excluded.entry, expires_at = excluded.expires_at`,
+		cep, string(data), expires)
+}
+
+func (c *sqliteCache) Delete(cep string) {
+	ctx, cancel := context.WithTimeout(context.Background(), sqliteTimeout)
+	defer cancel()
+	_, _ = c.db.ExecContext(ctx, `DELETE FROM addresses WHERE cep = ?`, cep)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cacheLookupsTotal conta as consultas ao cache por resultado: hit, miss e
+// stale.
+var cacheLookupsTotal = &counterVec{}
+
+// CacheStats resume o conteúdo de um cache.
+type CacheStats struct {
+	Entries  int
+	Expired  int
+	Negative int
+}
+
+// count soma e a stats.
+func (s *CacheStats) count(e CacheEntry) {
+	s.Entries++
+	if e.Expired() {
+		s.Expired++
+	}
+	if e.NotFound {
+		s.Negative++
+	}
+}
+
+// cacheInspector é implementado pelos caches que sabem resumir o próprio
+// conteúdo.
+type cacheInspector interface {
+	Stats() (CacheStats, error)
+}
+
+// cacheClearer é implementado pelos caches que podem ser esvaziados.
+type cacheClearer interface {
+	Clear() error
+}
+
+// defaultStatsPath retorna ~/.cache/cep/stats.json (ou equivalente do SO).
+func defaultStatsPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "cep", "stats.json")
+}
+
+// persistedStats são os contadores de acertos do cache somados entre
+// execuções.
+type persistedStats struct {
+	Counts map[string]int64 `json:"counts"`
+	Since  time.Time        `json:"since"`
+}
+
+func loadPersistedStats(path string) persistedStats {
+	s := persistedStats{Counts: map[string]int64{}}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &s)
+	}
+	if s.Counts == nil {
+		s.Counts = map[string]int64{}
+	}
+	return s
+}
+
+// savePersistedStats soma os contadores desta execução aos salvos em path.
+func savePersistedStats(path string, counts map[string]int64) error {
+	if path == "" || len(counts) == 0 {
+		return nil
+	}
+	s := loadPersistedStats(path)
+	if s.Since.IsZero() {
+		s.Since = time.Now()
+	}
+	for k, v := range counts {
+		s.Counts[k] += v
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCacheKey(t *testing.T) {
+	tests := []struct {
+		country, cep, want string
+	}{
+		{"BR", "01001000", "BR-01001000"},
+		{"BR", "01001-000", "BR-01001000"},
+		{"", "01001000", "BR-01001000"},
+		{"br", "01001000", "BR-01001000"},
+		{"DE", "01001", "DE-01001"},
+		{"US", "01001", "US-01001"},
+		{"GB", "SW1A 1AA", "GB-SW1A1AA"},
+		{"GB", "sw1a 1aa", "GB-SW1A1AA"},
+		{"NL", "1012 AB", "NL-1012AB"},
+		{"IE", "ABCDEFG", "IE-ABCDEFG"},
+		{"BR", "../../etc", "BR-ETC"},
+		{"US", " - ", ""},
+		{"BR", "", ""},
+	}
+	for _, tt := range tests {
+		if got := cacheKey(tt.country, tt.cep); got != tt.want {
+			t.Errorf("cacheKey(%q, %q) = %q, quero %q", tt.country, tt.cep, got, tt.want)
+		}
+	}
+}
+
+// Códigos iguais de países diferentes não podem dividir a entrada do cache.
+func TestResolverCacheByCountry(t *testing.T) {
+	cache := newMemoryCache(10)
+	resolver := func(country, city string) *Resolver {
+		return &Resolver{
+			Providers: []Provider{stubProvider{name: "Zippopotam", addr: Address{CEP: "01001", City: city}}},
+			Strategy:  raceStrategy,
+			Timeout:   time.Second,
+			Country:   country,
+			Cache:     cache,
+			CacheTTL:  time.Hour,
+		}
+	}
+	de := resolver("DE", "Dresden").Resolve(context.Background(), "01001")
+	us := resolver("US", "Agawam").Resolve(context.Background(), "01001")
+	if us.Results[0].CacheHit {
+		t.Fatal("a consulta dos EUA veio do cache da Alemanha")
+	}
+	if got := de.Results[0].Addr.City; got != "Dresden" {
+		t.Errorf("DE: cidade %q", got)
+	}
+	if got := us.Results[0].Addr.City; got != "Agawam" {
+		t.Errorf("US: cidade %q", got)
+	}
+}
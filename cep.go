@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// normalizeCEP remove a formatação usual de um CEP brasileiro (espaços,
+// hífen e pontos), de modo que "01310-100", "01.310-100" e " 01310 100 "
+// virem "01310100".
+func normalizeCEP(cep string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '-', '.':
+			return -1
+		}
+		return r
+	}, cep)
+}
+
+// formatCEP escreve um CEP de 8 dígitos como 01310-100; outros ficam
+// como estão.
+func formatCEP(cep string) string {
+	if len(cep) != 8 || validateCEP(cep) != nil {
+		return cep
+	}
+	return cep[:5] + "-" + cep[5:]
+}
+
+// invalidCEPError explica por que um CEP foi recusado sem ser consultado.
+type invalidCEPError struct {
+	reason string
+}
+
+func (e *invalidCEPError) Error() string { return ErrInvalidCEP.Error() + ": " + e.reason }
+
+func (e *invalidCEPError) Unwrap() error { return ErrInvalidCEP }
+
+// validateCEP confere se cep, já normalizado, tem exatamente 8 dígitos.
+func validateCEP(cep string) error {
+	switch {
+	case cep == "":
+		return &invalidCEPError{tr("vazio")}
+	case strings.ContainsFunc(cep, func(r rune) bool { return r < '0' || r > '9' }):
+		return &invalidCEPError{tr("deve ter só dígitos")}
+	case len(cep) != 8:
+		return &invalidCEPError{tr("tem %d dígitos, deve ter 8", len(cep))}
+	}
+	return nil
+}
+
+// checkCEPs valida os CEPs brasileiros de ceps antes de qualquer consulta,
+// escrevendo em w o motivo de cada recusa. Para outros países não há
+// validação.
+func checkCEPs(w io.Writer, country string, ceps []string) bool {
+	if !strings.EqualFold(country, "BR") {
+		return true
+	}
+	ok := true
+	for _, cep := range ceps {
+		var ie *invalidCEPError
+		if err := validateCEP(normalizeCEP(cep)); errors.As(err, &ie) {
+			fmt.Fprintln(w, paint(w, colorError, tr("CEP %s inválido: %s", cep, ie.reason)))
+			ok = false
+		}
+	}
+	return ok
+}
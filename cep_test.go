@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeCEP(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"01310100", "01310100"},
+		{"01310-100", "01310100"},
+		{"01.310-100", "01310100"},
+		{" 01310 100 ", "01310100"},
+		{"\t01310-100\t", "01310100"},
+		{"0131a100", "0131a100"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeCEP(tt.in); got != tt.want {
+			t.Errorf("normalizeCEP(%q) = %q, quero %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestValidateCEP(t *testing.T) {
+	tests := []struct {
+		cep   string
+		valid bool
+	}{
+		{"01310100", true},
+		{"00000000", true},
+		{"", false},
+		{"0131010", false},
+		{"013101000", false},
+		{"0131a100", false},
+		{"01310-100", false},
+		{"０１３１０１００", false},
+	}
+	for _, tt := range tests {
+		err := validateCEP(tt.cep)
+		if (err == nil) != tt.valid {
+			t.Errorf("validateCEP(%q) = %v, válido: %v", tt.cep, err, tt.valid)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidCEP) {
+			t.Errorf("validateCEP(%q) = %v, não é ErrInvalidCEP", tt.cep, err)
+		}
+	}
+}
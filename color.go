@@ -0,0 +1,26 @@
+package main
+
+import (
+	"io"
+	"os"
+)
+
+// noColor desliga as cores, por --no-color ou pela variável NO_COLOR.
+var noColor = os.Getenv("NO_COLOR") != ""
+
+// Códigos ANSI usados na saída de texto.
+const (
+	colorWinner = "1;32"
+	colorLabel  = "36"
+	colorError  = "31"
+)
+
+// paint colore s com o código ANSI code se w for um terminal e as cores não
+// estiverem desligadas.
+func paint(w io.Writer, code, s string) string {
+	f, ok := w.(*os.File)
+	if noColor || !ok || !isTerminal(f) {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
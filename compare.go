@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// fieldLabels traz o rótulo exibido de cada campo de fieldNames.
+var fieldLabels = map[string]string{
+	"cep":          "CEP",
+	"street":       "Rua",
+	"complement":   "Complemento",
+	"neighborhood": "Bairro",
+	"city":         "Cidade",
+	"state":        "Estado",
+	"country":      "País",
+	"ddd":          "DDD",
+	"ibge":         "Código IBGE",
+	"siafi":        "Código SIAFI",
+	"gia":          "Código GIA",
+	"latitude":     "Latitude",
+	"longitude":    "Longitude",
+	"state_name":   "Nome do estado",
+	"region":       "Região",
+	"timezone":     "Fuso horário",
+}
+
+// printComparison mostra lado a lado o endereço devolvido por cada provider,
+// marcando com * os campos em que eles discordam. Campos vazios não contam
+// como discordância.
+func printComparison(w io.Writer, results []APIResult) {
+	var ok []APIResult
+	for _, res := range results {
+		if res.Err != nil {
+			fmt.Fprint(w, tr("Erro na %s: %v\n", res.Source, res.Err))
+			continue
+		}
+		ok = append(ok, res)
+	}
+	if len(ok) == 0 {
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprint(tw, " \t"+tr("Campo"))
+	for _, res := range ok {
+		fmt.Fprintf(tw, "\t%s", res.Source)
+	}
+	fmt.Fprintln(tw)
+
+	diffs := 0
+	for _, name := range addressFieldNames {
+		values := make([]string, len(ok))
+		empty := true
+		for i := range ok {
+			values[i] = *addressFields[name](&ok[i].Addr)
+			empty = empty && values[i] == ""
+		}
+		if empty {
+			continue
+		}
+
+		mark := " "
+		if disagree(values) {
+			mark = "*"
+			diffs++
+		}
+		fmt.Fprintf(tw, "%s\t%s", mark, tr(fieldLabels[name]))
+		for _, v := range values {
+			if v == "" {
+				v = "-"
+			}
+			fmt.Fprintf(tw, "\t%s", v)
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+
+	if diffs > 0 {
+		fmt.Fprint(w, tr("\n%d campo(s) com divergência (*)\n", diffs))
+	} else {
+		fmt.Fprintln(w, tr("\nTodos os providers concordam"))
+	}
+}
+
+// disagree informa se há valores não vazios diferentes entre si, ignorando
+// maiúsculas e espaços nas pontas.
+func disagree(values []string) bool {
+	var first string
+	for _, v := range values {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if v == "" {
+			continue
+		}
+		if first == "" {
+			first = v
+		} else if v != first {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// Os scripts de completion delegam ao próprio programa (cep __complete), para
+// que subcomandos, opções e providers configurados fiquem sempre em dia.
+const (
+	bashCompletion = `_cep() {
+	local IFS=$'\n'
+	COMPREPLY=($(cep __complete "${COMP_WORDS[@]:1:COMP_CWORD}"))
+}
+complete -o default -F _cep cep
+`
+	zshCompletion = `#compdef cep
+_cep() {
+	local -a reply
+	reply=(${(f)"$(cep __complete "${(@)words[2,CURRENT]}")"})
+	if (( ${#reply} )); then
+		compadd -- $reply
+	else
+		_files
+	fi
+}
+compdef _cep cep
+`
+	fishCompletion = `complete -c cep -a '(cep __complete (commandline -opc)[2..-1] (commandline -ct))'
+`
+)
+
+// runCompletion escreve o script de completion do shell pedido.
+func runCompletion(args []string) int {
+	if len(args) != 1 {
+		fmt.Println(tr("Uso: go run main.go completion bash|zsh|fish"))
+		return exitUsage
+	}
+	switch args[0] {
+	case "bash":
+		fmt.Print(bashCompletion)
+	case "zsh":
+		fmt.Print(zshCompletion)
+	case "fish":
+		fmt.Print(fishCompletion)
+	default:
+		fmt.Print(tr("shell desconhecido: %s (use %s)\n", args[0], strings.Join(completionShells, ", ")))
+		return exitUsage
+	}
+	return exitOK
+}
+
+// runComplete recebe as palavras já digitadas depois do nome do programa,
+// sendo a última a que está sendo completada, e escreve uma opção por linha.
+func runComplete(words []string) int {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	for _, c := range complete(words) {
+		fmt.Println(c)
+	}
+	return exitOK
+}
+
+func complete(words []string) []string {
+	cur, prev := words[len(words)-1], words[:len(words)-1]
+
+	cmd, ok := findCommand("lookup"), false
+	if len(prev) > 0 {
+		if c := findCommand(prev[0]); c != nil {
+			cmd, ok = c, true
+		}
+	}
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if cmd.flags != nil {
+		cmd.flags(fs)
+	}
+
+	// Valor de uma opção
+	if len(prev) > 0 && strings.HasPrefix(prev[len(prev)-1], "-") {
+		if f := fs.Lookup(strings.TrimLeft(prev[len(prev)-1], "-")); f != nil && !isBoolFlag(f) {
+			return completeValue(f.Name, cur, prev)
+		}
+	}
+
+	if strings.HasPrefix(cur, "-") {
+		var names []string
+		fs.VisitAll(func(f *flag.Flag) { names = append(names, "--"+f.Name) })
+		return filterPrefix(names, cur)
+	}
+	if !ok && len(prev) == 0 {
+		// Primeira palavra: um subcomando ou, sem ele, um CEP para lookup
+		var names []string
+		for _, c := range commands {
+			if c.usage != "" {
+				names = append(names, c.name)
+			}
+		}
+		return filterPrefix(names, cur)
+	}
+	return filterPrefix(cmd.args, cur)
+}
+
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// completeValue completa o valor da opção name. Nomes de arquivo ficam a
+// cargo do shell.
+func completeValue(name, cur string, prev []string) []string {
+	switch name {
+	case "strategy":
+		return filterPrefix(strategyNames(), cur)
+	case "format":
+		return filterPrefix(formatNames(), cur)
+	case "geocoder":
+		return filterPrefix([]string{"google", "nominatim"}, cur)
+	case "normalize":
+		return completeList([]string{"ascii", "upper", "title"}, cur)
+	case "street-type":
+		return filterPrefix([]string{"expand", "abbrev"}, cur)
+	case "map":
+		return filterPrefix([]string{"google", "osm"}, cur)
+	case "uf":
+		return filterPrefix(slices.Sorted(maps.Keys(stateNames)), strings.ToUpper(cur))
+	case "xml-names":
+		return filterPrefix([]string{"json", "camel"}, cur)
+	case "prefer":
+		return filterPrefix(providerNames(prev), cur)
+	case "providers":
+		return completeList(providerNames(prev), cur)
+	case "fields":
+		return completeList(fieldNames, cur)
+	}
+	return nil
+}
+
+// completeList completa o último item de uma lista separada por vírgulas.
+func completeList(names []string, cur string) []string {
+	done, last := "", cur
+	if i := strings.LastIndex(cur, ","); i >= 0 {
+		done, last = cur[:i+1], cur[i+1:]
+	}
+	var out []string
+	for _, n := range filterPrefix(names, last) {
+		out = append(out, done+n)
+	}
+	return out
+}
+
+// providerNames lista os providers embutidos e os do arquivo de configuração
+// (o de --config, se estiver entre as palavras).
+func providerNames(words []string) []string {
+	var opts options
+	for i, w := range words {
+		if (w == "--config" || w == "-config") && i+1 < len(words) {
+			opts.config = words[i+1]
+		}
+	}
+	// Um erro na configuração só deixa de fora os providers dela
+	opts.loadConfig()
+
+	var names []string
+	for _, p := range Providers() {
+		names = append(names, p.Name())
+	}
+	return names
+}
+
+func filterPrefix(values []string, prefix string) []string {
+	var out []string
+	for _, v := range values {
+		if strings.HasPrefix(strings.ToLower(v), strings.ToLower(prefix)) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFilterPrefix(t *testing.T) {
+	values := []string{"BrasilAPI", "ViaCEP", "ApiCEP", "AwesomeAPI"}
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"", values},
+		{"a", []string{"ApiCEP", "AwesomeAPI"}},
+		{"VIA", []string{"ViaCEP"}},
+		{"x", nil},
+	}
+	for _, tt := range tests {
+		if got := filterPrefix(values, tt.prefix); !slices.Equal(got, tt.want) {
+			t.Errorf("filterPrefix(%q) = %v, quero %v", tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestCompleteList(t *testing.T) {
+	names := []string{"BrasilAPI", "ViaCEP", "OpenCEP"}
+	tests := []struct {
+		cur  string
+		want []string
+	}{
+		{"", names},
+		{"vi", []string{"ViaCEP"}},
+		{"BrasilAPI,", []string{"BrasilAPI,BrasilAPI", "BrasilAPI,ViaCEP", "BrasilAPI,OpenCEP"}},
+		{"BrasilAPI,o", []string{"BrasilAPI,OpenCEP"}},
+		{"BrasilAPI,ViaCEP,op", []string{"BrasilAPI,ViaCEP,OpenCEP"}},
+		{"BrasilAPI,x", nil},
+	}
+	for _, tt := range tests {
+		if got := completeList(names, tt.cur); !slices.Equal(got, tt.want) {
+			t.Errorf("completeList(%q) = %v, quero %v", tt.cur, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config é o conteúdo do arquivo de configuração (config.yaml).
+type Config struct {
+	Providers []TemplateProviderConfig `yaml:"providers"`
+	Plugins   []ExecProviderConfig     `yaml:"plugins"`
+
+	// OfflineDataset aponta para CSVs extras da base do provider Offline.
+	OfflineDataset []string `yaml:"offline_dataset"`
+
+	// ProviderSettings ajusta cada provider pelo nome, por exemplo:
+	//
+	//	provider_settings:
+	//	  Espelho:
+	//	    priority: 100
+	//	    weight: 3
+	ProviderSettings map[string]ProviderSettings `yaml:"provider_settings"`
+
+	// CircuitBreaker configura o circuit breaker de todos os providers.
+	CircuitBreaker *BreakerSettings `yaml:"circuit_breaker"`
+
+	Cache CacheConfig `yaml:"cache"`
+
+	// Defaults dá valores às opções de linha de comando, pelo nome, quando
+	// elas não forem informadas nem definidas por CEP_*:
+	//
+	//	defaults:
+	//	  timeout: 2s
+	//	  providers: [BrasilAPI, ViaCEP]
+	//	  format: json
+	//	  cache-ttl: 72h
+	Defaults map[string]any `yaml:"defaults"`
+
+	// Tokens guarda as credenciais dos serviços; as variáveis de ambiente
+	// correspondentes têm precedência.
+	Tokens Tokens `yaml:"tokens"`
+
+	Server ServerConfig `yaml:"server"`
+}
+
+// ServerConfig configura o subcomando serve.
+type ServerConfig struct {
+	// APIKeys são as chaves de API aceitas, pelo nome de quem as usa (que
+	// aparece no log de acesso). Com alguma chave, a API exige uma delas:
+	//
+	//	server:
+	//	  api_keys:
+	//	    loja: 3f9a0c...
+	//	    backoffice: 7be21d...
+	APIKeys map[string]string `yaml:"api_keys"`
+}
+
+// Tokens são as credenciais lidas do arquivo de configuração.
+type Tokens struct {
+	// CEPAberto equivale a CEPABERTO_TOKEN.
+	CEPAberto string `yaml:"cepaberto"`
+	// GoogleMaps equivale a GOOGLE_MAPS_API_KEY.
+	GoogleMaps string `yaml:"google_maps"`
+	// NominatimUserAgent equivale a NOMINATIM_USER_AGENT.
+	NominatimUserAgent string `yaml:"nominatim_user_agent"`
+}
+
+// CacheConfig escolhe onde os endereços resolvidos são guardados além da
+// memória. Com um plugin, Redis ou SQLite configurado, ele substitui o cache
+// em disco; se mais de um estiver configurado, vale o primeiro nessa ordem.
+type CacheConfig struct {
+	// Plugin é o caminho de um plugin Go (.so) que implementa o cache; veja
+	// pluginCacheStore.
+	Plugin string       `yaml:"plugin"`
+	Redis  RedisConfig  `yaml:"redis"`
+	SQLite SQLiteConfig `yaml:"sqlite"`
+}
+
+// ProviderSettings são os ajustes de um provider específico.
+type ProviderSettings struct {
+	// Priority ordena os providers (maior primeiro) quando --providers não é
+	// informado, definindo a ordem do fallback/hedge e o desempate da corrida.
+	Priority int `yaml:"priority"`
+	// Weight é o peso do voto do provider no quórum (padrão 1).
+	Weight int `yaml:"weight"`
+	// Retry substitui, para este provider, a política de --retries.
+	Retry *RetryPolicy `yaml:"retry"`
+	// Timeout limita cada consulta a este provider (ex.: 700ms).
+	Timeout time.Duration `yaml:"timeout"`
+	// RateLimit limita a taxa de requisições a este provider.
+	RateLimit *RateLimit `yaml:"rate_limit"`
+	// CircuitBreaker substitui, para este provider, o circuit_breaker global.
+	CircuitBreaker *BreakerSettings `yaml:"circuit_breaker"`
+}
+
+// settingsFor devolve os ajustes do provider name, ignorando maiúsculas.
+func (c Config) settingsFor(name string) ProviderSettings {
+	if s, ok := c.ProviderSettings[name]; ok {
+		return s
+	}
+	for k, s := range c.ProviderSettings {
+		if strings.EqualFold(k, name) {
+			return s
+		}
+	}
+	return ProviderSettings{}
+}
+
+// breakerFor devolve a configuração do circuit breaker do provider name.
+func (c Config) breakerFor(name string) BreakerSettings {
+	if b := c.settingsFor(name).CircuitBreaker; b != nil {
+		return *b
+	}
+	if c.CircuitBreaker != nil {
+		return *c.CircuitBreaker
+	}
+	return defaultBreakerSettings
+}
+
+// weights devolve o peso de cada provider de providers no quórum.
+func (c Config) weights(providers []Provider) map[string]int {
+	w := make(map[string]int, len(providers))
+	for _, p := range providers {
+		w[p.Name()] = max(c.settingsFor(p.Name()).Weight, 1)
+	}
+	return w
+}
+
+// sortByPriority ordena providers por prioridade decrescente, mantendo a
+// ordem de registro entre providers de mesma prioridade.
+func (c Config) sortByPriority(providers []Provider) {
+	sort.SliceStable(providers, func(i, j int) bool {
+		return c.settingsFor(providers[i].Name()).Priority > c.settingsFor(providers[j].Name()).Priority
+	})
+}
+
+// defaultConfigPath retorna ~/.config/cep/config.yaml (ou equivalente do SO).
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "cep", "config.yaml")
+}
+
+// loadConfig lê o arquivo em path. Se explicit for falso, a ausência do
+// arquivo não é erro e resulta numa configuração vazia.
+func loadConfig(path string, explicit bool) (Config, error) {
+	var cfg Config
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// debugBodyLimit é o máximo de bytes do corpo de cada resposta mostrado no
+// modo --debug.
+const debugBodyLimit = 2048
+
+// debugLog escreve as mensagens do modo --debug na saída de erro, sem
+// misturar as linhas de goroutines diferentes.
+type debugLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *debugLog) printf(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprintf(l.w, "[debug] "+format, args...)
+}
+
+type debugNameKey struct{}
+
+// debugTransport registra a URL, o status, a latência e o corpo de cada
+// resposta HTTP, identificando o provider que fez a requisição.
+type debugTransport struct {
+	base http.RoundTripper
+	log  *debugLog
+}
+
+func (t debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	name, _ := req.Context().Value(debugNameKey{}).(string)
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		t.log.printf("%s %s %s: %v em %s\n", name, req.Method, redactURL(req.URL), err, elapsed)
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return resp, err
+	}
+	shown := body
+	if len(shown) > debugBodyLimit {
+		shown = append(shown[:debugBodyLimit:debugBodyLimit], "…"...)
+	}
+	t.log.printf("%s %s %s -> %s em %s\n%s\n", name, req.Method, redactURL(req.URL), resp.Status, elapsed, bytes.TrimSpace(shown))
+	return resp, nil
+}
+
+// redactURL esconde senhas e chaves de API (como a do Google) da URL.
+func redactURL(u *url.URL) string {
+	q := u.Query()
+	for _, k := range []string{"key", "token", "api_key", "apikey"} {
+		if q.Has(k) {
+			q.Set(k, "xxxxx")
+		}
+	}
+	r := *u
+	r.RawQuery = q.Encode()
+	return r.Redacted()
+}
+
+// debugProvider deixa a consulta a Provider terminar mesmo depois que a
+// estratégia desiste dela (porque outro provider venceu, por exemplo), para
+// mostrar a resposta de todos. Só o prazo geral da consulta é respeitado.
+type debugProvider struct {
+	Provider
+	log     *debugLog
+	pending *sync.WaitGroup
+}
+
+func (d debugProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	inner := context.WithValue(context.WithoutCancel(ctx), debugNameKey{}, d.Name())
+	cancel := func() {}
+	if deadline, ok := ctx.Deadline(); ok {
+		inner, cancel = context.WithDeadline(inner, deadline)
+	}
+
+	type result struct {
+		addr Address
+		err  error
+	}
+	ch := make(chan result, 1)
+	d.pending.Add(1)
+	go func() {
+		defer d.pending.Done()
+		defer cancel()
+		start := time.Now()
+		addr, err := d.Provider.Fetch(inner, cep)
+		elapsed := time.Since(start).Round(time.Millisecond)
+		note := ""
+		if ctx.Err() != nil {
+			// A estratégia já não espera por este resultado
+			note = " (descartado)"
+		}
+		if err != nil {
+			d.log.printf("%s: erro em %s%s: %v\n", d.Name(), elapsed, note, err)
+		} else {
+			d.log.printf("%s: %s, %s/%s em %s%s\n", d.Name(), addr.CEP, addr.City, addr.State, elapsed, note)
+		}
+		ch <- result{addr, err}
+	}()
+
+	select {
+	case r := <-ch:
+		return r.addr, r.err
+	case <-ctx.Done():
+		return Address{}, ctx.Err()
+	}
+}
+
+// enableDebug liga o modo --debug em providers e no cliente HTTP padrão. O
+// WaitGroup devolvido termina quando as consultas abandonadas terminarem.
+func enableDebug(providers []Provider) *sync.WaitGroup {
+	log := &debugLog{w: os.Stderr}
+	http.DefaultClient.Transport = debugTransport{base: http.DefaultTransport, log: log}
+
+	pending := &sync.WaitGroup{}
+	for i, p := range providers {
+		providers[i] = debugProvider{Provider: p, log: log, pending: pending}
+	}
+	return pending
+}
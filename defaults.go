@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// parseFlags interpreta args em fs e completa as opções não informadas com,
+// nesta ordem, as variáveis CEP_* (CEP_TIMEOUT para --timeout, CEP_CACHE_TTL
+// para --cache-ttl...) e a seção defaults do arquivo de configuração.
+func parseFlags(fs *flag.FlagSet, args []string) {
+	fs.Usage = func() { printFlagUsage(fs) }
+	fs.Parse(args)
+
+	explicit := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	set := func(name, value, origin string) {
+		if err := fs.Set(name, value); err != nil {
+			fmt.Fprint(os.Stderr, tr("valor inválido %q para %s em %s: %v\n", value, name, origin, err))
+			os.Exit(exitUsage)
+		}
+		explicit[name] = true
+	}
+	fs.VisitAll(func(f *flag.Flag) {
+		env := envName(f.Name)
+		if v, ok := os.LookupEnv(env); ok && !explicit[f.Name] {
+			set(f.Name, v, env)
+		}
+	})
+
+	path := defaultConfigPath()
+	if f := fs.Lookup("config"); f != nil && f.Value.String() != "" {
+		path = f.Value.String()
+	}
+	cfg, err := loadConfig(path, explicit["config"])
+	if err != nil {
+		// O erro aparece de novo, com o contexto certo, quando setup carregar
+		// a configuração
+		return
+	}
+	for name, value := range cfg.Defaults {
+		if fs.Lookup(name) == nil || explicit[name] {
+			continue
+		}
+		set(name, defaultValue(value), path)
+	}
+}
+
+// printFlagUsage substitui a ajuda de -h do pacote flag, traduzindo a
+// descrição de cada opção. Nas descrições que terminam numa lista de valores
+// ("formato da saída: text, json, ..."), só o começo é traduzido.
+func printFlagUsage(fs *flag.FlagSet) {
+	fmt.Fprint(fs.Output(), tr("Opções de %s:\n", fs.Name()))
+	fs.VisitAll(func(f *flag.Flag) {
+		if _, ok := catalog[lang][f.Usage]; ok {
+			f.Usage = tr(f.Usage)
+		} else if head, list, ok := strings.Cut(f.Usage, ": "); ok {
+			f.Usage = tr(head) + ": " + list
+		}
+	})
+	fs.PrintDefaults()
+}
+
+// envName devolve a variável de ambiente da opção name: CEP_ seguido do
+// nome em maiúsculas, com _ no lugar de -.
+func envName(name string) string {
+	return "CEP_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// defaultValue converte um valor da seção defaults para o texto da opção;
+// listas viram itens separados por vírgula.
+func defaultValue(v any) string {
+	if list, ok := v.([]any); ok {
+		items := make([]string, len(list))
+		for i, item := range list {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// distanceFormats são os formatos aceitos pelo subcomando distance.
+var distanceFormats = []string{"text", "json", "ndjson", "yaml", "xml", "csv", "tsv", "oneline"}
+
+func registerDistanceFlags(fs *flag.FlagSet) (*options, *string) {
+	return registerOptions(fs), fs.String("format", "text", "formato da saída: "+strings.Join(distanceFormats, ", "))
+}
+
+// runDistance resolve dois CEPs, cada um com a sua corrida entre os
+// providers, geocodifica-os e mostra a distância em linha reta entre eles.
+func runDistance(args []string) int {
+	fs := flag.NewFlagSet("cep distance", flag.ExitOnError)
+	opts, format := registerDistanceFlags(fs)
+	parseFlags(fs, args)
+
+	if fs.NArg() != 2 {
+		fmt.Println(tr("Uso: go run main.go distance [opções] <cep1> <cep2>"))
+		return exitUsage
+	}
+	if !slices.Contains(distanceFormats, *format) {
+		fmt.Println(tr("formato desconhecido: %s (use %s)", *format, strings.Join(distanceFormats, ", ")))
+		return exitUsage
+	}
+	ceps := fs.Args()
+	if !checkCEPs(os.Stdout, opts.country, ceps) {
+		return exitInvalidCEP
+	}
+
+	// Sem coordenadas não há distância
+	opts.geo = true
+	app, err := opts.setup()
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	defer app.Close()
+
+	lookups := app.resolver.ResolveAll(context.Background(), ceps, 2)
+	var ends [2]APIResult
+	for i, lookup := range lookups {
+		// Só as mensagens de erro e os avisos; os endereços saem juntos, no fim
+		printLookup(io.Discard, os.Stdout, app.resolver, ceps[i], lookup, writeNDJSON)
+		if !lookup.OK() {
+			return lookupExitCode(lookup)
+		}
+		for _, res := range lookup.Results {
+			if res.Err == nil {
+				ends[i] = res
+				break
+			}
+		}
+		if ends[i].Addr.Location == nil {
+			fmt.Println(tr("Sem coordenadas para o CEP %s", ceps[i]))
+			return exitProvidersError
+		}
+	}
+
+	d := distanceResult{
+		From: newJSONResult(ends[0]),
+		To:   newJSONResult(ends[1]),
+		KM:   math.Round(haversineKM(*ends[0].Addr.Location, *ends[1].Addr.Location)*100) / 100,
+	}
+	if err := d.write(os.Stdout, *format); err != nil {
+		fmt.Fprint(os.Stderr, tr("Erro ao escrever a saída: %v\n", err))
+		return exitUsage
+	}
+	return exitOK
+}
+
+// earthRadiusKM é o raio médio da Terra.
+const earthRadiusKM = 6371.0088
+
+// haversineKM é a distância em linha reta, pela superfície da Terra, entre a
+// e b.
+func haversineKM(a, b Location) float64 {
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat, dLng := rad(b.Latitude-a.Latitude), rad(b.Longitude-a.Longitude)
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(rad(a.Latitude))*math.Cos(rad(b.Latitude))*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * earthRadiusKM * math.Asin(math.Sqrt(h))
+}
+
+// distanceResult é a saída do subcomando distance.
+type distanceResult struct {
+	From jsonResult `json:"from"`
+	To   jsonResult `json:"to"`
+	KM   float64    `json:"distance_km"`
+}
+
+func (d distanceResult) write(w io.Writer, format string) error {
+	km := strconv.FormatFloat(d.KM, 'f', 2, 64)
+	switch format {
+	case "text":
+		fmt.Fprintln(w, paint(w, colorLabel, tr("De:")), oneLine(d.From.Address))
+		fmt.Fprintln(w, paint(w, colorLabel, tr("Para:")), oneLine(d.To.Address))
+		_, err := fmt.Fprintln(w, paint(w, colorLabel, tr("Distância:")), paint(w, colorWinner, km+" km"))
+		return err
+	case "oneline":
+		_, err := fmt.Fprintln(w, km+" km")
+		return err
+	case "tsv":
+		_, err := fmt.Fprintln(w, strings.Join([]string{d.From.CEP, d.To.CEP, km}, "\t"))
+		return err
+	case "csv":
+		if err := writeCSVRecord(w, []string{"from_cep", "to_cep", "distance_km"}); err != nil {
+			return err
+		}
+		return writeCSVRecord(w, []string{d.From.CEP, d.To.CEP, km})
+	case "json", "ndjson":
+		enc := json.NewEncoder(w)
+		if format == "json" {
+			enc.SetIndent("", "  ")
+		}
+		return enc.Encode(d)
+	case "yaml", "xml":
+		data, err := json.Marshal(d)
+		if err != nil {
+			return err
+		}
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+		if format == "xml" {
+			return writeXMLNode(w, "distance", doc.Content[0])
+		}
+		return writeYAMLNode(w, &doc)
+	}
+	return errors.New(tr("formato desconhecido: %s (use %s)", format, strings.Join(distanceFormats, ", ")))
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// providerPlan é um provider como montado por setup, antes dos wrappers.
+type providerPlan struct {
+	provider Provider
+	timeout  time.Duration
+	retries  int
+}
+
+var errDryRun error = localizedError("dry-run: requisição não enviada")
+
+// dryRunTransport escreve cada requisição, com os cabeçalhos, o corpo e o
+// proxy que seria usado, em vez de enviá-la.
+type dryRunTransport struct {
+	w io.Writer
+}
+
+func (t dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fmt.Fprintf(t.w, "   %s %s\n", req.Method, redactURL(req.URL))
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := strings.Join(req.Header[name], ", ")
+		if name == "Authorization" {
+			value = "xxxxx"
+		}
+		fmt.Fprintf(t.w, "   %s: %s\n", name, value)
+	}
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		req.Body.Close()
+		for _, line := range strings.Split(strings.TrimSpace(string(body)), "\n") {
+			fmt.Fprintf(t.w, "   | %s\n", line)
+		}
+	}
+
+	proxy, err := http.ProxyFromEnvironment(req)
+	switch {
+	case err != nil:
+		fmt.Fprint(t.w, tr("   proxy inválido: %v\n", err))
+	case proxy != nil:
+		fmt.Fprint(t.w, tr("   via proxy %s\n", proxy.Redacted()))
+	default:
+		fmt.Fprint(t.w, tr("   sem proxy\n"))
+	}
+	return nil, errDryRun
+}
+
+// printDryRun descreve em w a consulta de cada CEP de ceps: a estratégia, os
+// providers na ordem em que seriam acionados e as requisições de cada um.
+func printDryRun(w io.Writer, a *app, ceps []string) {
+	fmt.Fprint(w, tr("Estratégia: %s, prazo total de %s\n", a.strategy, a.resolver.Timeout))
+	if a.resolver.Cache == nil {
+		fmt.Fprint(w, tr("Cache: desligado\n"))
+	} else {
+		fmt.Fprint(w, tr("Cache: ligado (um CEP em cache não geraria requisições)\n"))
+	}
+
+	client := http.DefaultClient
+	defer func(t http.RoundTripper) { client.Transport = t }(client.Transport)
+	client.Transport = dryRunTransport{w: w}
+
+	for _, cep := range ceps {
+		fmt.Fprintf(w, "\nCEP %s:\n", cep)
+		for i, plan := range a.plan {
+			timeout := tr("padrão")
+			if plan.timeout > 0 {
+				timeout = plan.timeout.String()
+			}
+			fmt.Fprint(w, tr("%d. %s (timeout %s, %d nova(s) tentativa(s))\n", i+1, plan.provider.Name(), timeout, plan.retries))
+
+			switch p := plan.provider.(type) {
+			case execProvider:
+				args := append([]string{p.cfg.Command}, p.cfg.Args...)
+				if p.cfg.Input == "argv" {
+					args = append(args, cep)
+				}
+				fmt.Fprint(w, tr("   executaria %s (CEP via %s)\n", strings.Join(args, " "), p.cfg.Input))
+			case goPluginProvider:
+				fmt.Fprint(w, tr("   plugin Go: as requisições não podem ser previstas\n"))
+			case *offlineProvider:
+				fmt.Fprint(w, tr("   consulta a base local, sem rede\n"))
+			default:
+				p.Fetch(context.Background(), cep)
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Erros que classificam a falha de uma consulta. Os providers e as
+// estratégias os embrulham (use errors.Is) para que scripts e quem usa o
+// pacote distingam "CEP não existe" de "problema de rede".
+var (
+	ErrNotFound           error = localizedError("CEP não encontrado")
+	ErrInvalidCEP         error = localizedError("CEP inválido")
+	ErrTimeout            error = localizedError("tempo esgotado")
+	ErrAllProvidersFailed error = localizedError("todos os providers falharam")
+)
+
+// localizedError é um erro fixo cuja mensagem só passa por tr quando é
+// impressa: as variáveis de pacote são criadas antes de --lang ser lido.
+type localizedError string
+
+func (e localizedError) Error() string { return tr(string(e)) }
+
+// ctxError converte o término de ctx em erro, classificando o fim do prazo
+// como ErrTimeout.
+func ctxError(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrTimeout, ctx.Err())
+	}
+	return ctx.Err()
+}
+
+// ProvidersError reúne as falhas de todos os providers de uma consulta. Só
+// Kind é exposto a errors.Is, para que uma falha de rede de um provider não
+// faça o conjunto parecer ErrNotFound (ou vice-versa).
+type ProvidersError struct {
+	Kind error
+	Errs []error
+}
+
+func (e *ProvidersError) Error() string {
+	return fmt.Sprintf("%v:\n%v", e.Kind, errors.Join(e.Errs...))
+}
+
+func (e *ProvidersError) Unwrap() error { return e.Kind }
+
+// allFailed junta os erros de todos os providers de uma consulta. Se todos
+// disserem que o CEP não existe (ou é inválido), o erro é classificado como
+// tal; senão, como ErrAllProvidersFailed.
+func allFailed(errs []error) error {
+	kind := ErrAllProvidersFailed
+	for _, k := range []error{ErrNotFound, ErrInvalidCEP} {
+		all := len(errs) > 0
+		for _, err := range errs {
+			all = all && errors.Is(err, k)
+		}
+		if all {
+			kind = k
+			break
+		}
+	}
+	return &ProvidersError{Kind: kind, Errs: errs}
+}
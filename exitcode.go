@@ -0,0 +1,55 @@
+package main
+
+import "errors"
+
+// Códigos de saída do programa, para que scripts possam reagir à causa da
+// falha:
+//
+//	0  sucesso
+//	1  erro de uso ou de configuração
+//	2  CEP inválido
+//	3  CEP não encontrado
+//	4  tempo esgotado
+//	5  todos os providers falharam
+//	6  o endereço informado não confere com o do CEP (validate)
+const (
+	exitOK             = 0
+	exitUsage          = 1
+	exitInvalidCEP     = 2
+	exitNotFound       = 3
+	exitTimeout        = 4
+	exitProvidersError = 5
+	exitMismatch       = 6
+)
+
+// exitCode devolve o código de saída de uma consulta que falhou com err.
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, ErrInvalidCEP):
+		return exitInvalidCEP
+	case errors.Is(err, ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, ErrTimeout):
+		return exitTimeout
+	default:
+		return exitProvidersError
+	}
+}
+
+// lookupExitCode devolve o código de saída de uma consulta.
+func lookupExitCode(l Lookup) int {
+	if l.OK() {
+		return exitOK
+	}
+	if l.TimedOut {
+		return exitTimeout
+	}
+	if len(l.Results) == 1 {
+		return exitCode(l.Results[0].Err)
+	}
+	errs := make([]error, len(l.Results))
+	for i, res := range l.Results {
+		errs[i] = res.Err
+	}
+	return exitCode(allFailed(errs))
+}
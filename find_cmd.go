@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// findDebounce é quanto find espera o usuário parar de digitar antes de
+// buscar, para não consultar o ViaCEP a cada tecla.
+const findDebounce = 300 * time.Millisecond
+
+// findVisible é quantos resultados find mostra de uma vez.
+const findVisible = 10
+
+func registerFindFlags(fs *flag.FlagSet) (opts *options, uf, city *string) {
+	opts = registerOptions(fs)
+	uf = fs.String("uf", "", "UF inicial da busca, ex.: SP")
+	city = fs.String("city", "", "cidade inicial da busca")
+	return opts, uf, city
+}
+
+// runFind abre a busca interativa por logradouro: os CEPs da rua vão
+// aparecendo enquanto ela é digitada.
+func runFind(args []string) int {
+	fs := flag.NewFlagSet("cep find", flag.ExitOnError)
+	opts, uf, city := registerFindFlags(fs)
+	parseFlags(fs, args)
+
+	app, err := opts.setup()
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	defer app.Close()
+
+	m := findModel{app: app}
+	for i, v := range []string{strings.ToUpper(*uf), *city, ""} {
+		in := textinput.New()
+		in.SetValue(v)
+		m.inputs[i] = in
+	}
+	m.inputs[0].Placeholder, m.inputs[0].CharLimit = "SP", 2
+	m.inputs[1].Placeholder = "São Paulo"
+	m.inputs[2].Placeholder = "Paulista"
+	// O agente normalmente já começa pela rua
+	m.focus = 2
+	if *uf == "" {
+		m.focus = 0
+	} else if *city == "" {
+		m.focus = 1
+	}
+	m.inputs[m.focus].Focus()
+
+	if _, err := tea.NewProgram(m, tea.WithAltScreen()).Run(); err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	return exitOK
+}
+
+// findQuery é uma busca por endereço.
+type findQuery struct {
+	uf, city, street string
+}
+
+// ready informa se a busca tem o mínimo exigido pelo ViaCEP.
+func (q findQuery) ready() bool {
+	_, ok := stateNames[q.uf]
+	return ok && utf8.RuneCountInString(q.city) >= 3 && utf8.RuneCountInString(q.street) >= 3
+}
+
+// findTickMsg dispara a busca seq, se nada tiver sido digitado desde então.
+type findTickMsg int
+
+type findDoneMsg struct {
+	query findQuery
+	addrs []Address
+	err   error
+}
+
+type findModel struct {
+	app *app
+	// inputs são a UF, a cidade e a rua, nesta ordem.
+	inputs [3]textinput.Model
+	focus  int
+
+	// seq conta as edições, para que só a última dispare a busca.
+	seq   int
+	busy  bool
+	query findQuery
+	addrs []Address
+	err   error
+
+	selected int
+	message  string
+}
+
+func (m findModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m findModel) current() findQuery {
+	return findQuery{
+		uf:     strings.ToUpper(strings.TrimSpace(m.inputs[0].Value())),
+		city:   strings.TrimSpace(m.inputs[1].Value()),
+		street: strings.TrimSpace(m.inputs[2].Value()),
+	}
+}
+
+// search busca q no ViaCEP e aplica aos endereços os mesmos ajustes das
+// consultas por CEP.
+func (m findModel) search(q findQuery) tea.Cmd {
+	resolver := m.app.resolver
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), resolver.Timeout)
+		defer cancel()
+		addrs, err := searchViaCEP(ctx, q.uf, q.city, q.street)
+		for i := range addrs {
+			for _, t := range resolver.Transforms {
+				addrs[i] = t(addrs[i])
+			}
+		}
+		return findDoneMsg{query: q, addrs: addrs, err: err}
+	}
+}
+
+func (m findModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc:
+			return m, tea.Quit
+		case tea.KeyTab, tea.KeyShiftTab:
+			m.inputs[m.focus].Blur()
+			if msg.Type == tea.KeyTab {
+				m.focus = (m.focus + 1) % len(m.inputs)
+			} else {
+				m.focus = (m.focus + len(m.inputs) - 1) % len(m.inputs)
+			}
+			return m, m.inputs[m.focus].Focus()
+		case tea.KeyUp:
+			m.selected = max(m.selected-1, 0)
+			return m, nil
+		case tea.KeyDown:
+			m.selected = max(min(m.selected+1, len(m.addrs)-1), 0)
+			return m, nil
+		case tea.KeyEnter:
+			if m.selected >= len(m.addrs) {
+				return m, nil
+			}
+			return m, copyToClipboard("CEP "+m.addrs[m.selected].CEP, m.addrs[m.selected].CEP)
+		}
+		before := m.inputs[m.focus].Value()
+		var cmd tea.Cmd
+		m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+		if m.inputs[m.focus].Value() == before {
+			return m, cmd
+		}
+		m.seq++
+		seq := m.seq
+		debounce := tea.Tick(findDebounce, func(time.Time) tea.Msg { return findTickMsg(seq) })
+		return m, tea.Batch(cmd, debounce)
+
+	case findTickMsg:
+		q := m.current()
+		if int(msg) != m.seq || !q.ready() || q == m.query {
+			return m, nil
+		}
+		m.query, m.busy, m.message = q, true, ""
+		return m, m.search(q)
+
+	case findDoneMsg:
+		// Uma resposta atrasada de uma busca anterior não apaga a atual
+		if msg.query != m.query {
+			return m, nil
+		}
+		m.busy, m.addrs, m.err, m.selected = false, msg.addrs, msg.err, 0
+		return m, nil
+
+	case copiedMsg:
+		m.message = tr("%s copiado", string(msg))
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+	return m, cmd
+}
+
+func (m findModel) View() string {
+	var b strings.Builder
+	labels := []string{tr("UF"), tr("Cidade"), tr("Rua")}
+	var boxes []string
+	for i, in := range m.inputs {
+		boxes = append(boxes, tuiTitle.Render(fmt.Sprintf("%-7s", labels[i]))+in.View())
+	}
+	b.WriteString(tuiBox.Render(strings.Join(boxes, "\n")))
+	b.WriteString("\n\n")
+
+	switch q := m.current(); {
+	case m.busy:
+		b.WriteString(tuiFaint.Render(tr("Buscando…")))
+	case !q.ready():
+		b.WriteString(tuiFaint.Render(tr("Informe a UF e pelo menos 3 letras da cidade e da rua")))
+	case m.err != nil:
+		b.WriteString(tuiFail.Render(tr("Erro na busca: %v", m.err)))
+	case m.query.street != "" && len(m.addrs) == 0:
+		b.WriteString(tr("Nenhum endereço encontrado"))
+	case len(m.addrs) > 0:
+		// Mostra uma janela de findVisible resultados em volta do escolhido
+		start := min(max(m.selected-findVisible/2, 0), max(len(m.addrs)-findVisible, 0))
+		var lines []string
+		for i := start; i < min(start+findVisible, len(m.addrs)); i++ {
+			a := m.addrs[i]
+			detail := strings.Join(nonEmpty(a.Complement, a.Neighborhood), " · ")
+			line := fmt.Sprintf("%-9s  %s", a.CEP, a.Street)
+			if detail != "" {
+				line += "  " + tuiFaint.Render(detail)
+			}
+			if i == m.selected {
+				line = tuiSelected.Render(line)
+			}
+			lines = append(lines, line)
+		}
+		title := tr("%d endereços", len(m.addrs))
+		b.WriteString(tuiBox.Render(tuiTitle.Render(title) + "\n" + strings.Join(lines, "\n")))
+	}
+	b.WriteString("\n\n")
+	if m.message != "" {
+		b.WriteString(m.message + "\n\n")
+	}
+	b.WriteString(tuiFaint.Render(tr("Digite para buscar · Tab troca de campo · ↑/↓ escolhe · Enter copia o CEP · Esc sai")))
+	return b.String()
+}
+
+// nonEmpty devolve os valores de vs que não estão vazios.
+func nonEmpty(vs ...string) []string {
+	var out []string
+	for _, v := range vs {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
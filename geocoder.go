@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+)
+
+// Geocoder obtém as coordenadas de um endereço já resolvido pela corrida.
+type Geocoder interface {
+	Name() string
+	Geocode(ctx context.Context, addr Address) (*Location, error)
+}
+
+// ReverseGeocoder é um Geocoder que também encontra o endereço mais próximo
+// de um ponto.
+type ReverseGeocoder interface {
+	Geocoder
+	Reverse(ctx context.Context, loc Location) (Address, error)
+}
+
+// configuredGeocoders devolve os geocoders habilitados pelo ambiente.
+func configuredGeocoders() []Geocoder {
+	var geocoders []Geocoder
+	if key := os.Getenv("GOOGLE_MAPS_API_KEY"); key != "" {
+		geocoders = append(geocoders, googleGeocoder{key: key})
+	}
+	if ua := os.Getenv("NOMINATIM_USER_AGENT"); ua != "" {
+		geocoders = append(geocoders, nominatimGeocoder{userAgent: ua})
+	}
+	return geocoders
+}
+
+// selectGeocoders devolve os geocoders de --geo: o de nome name ou, sem ele,
+// os habilitados pelo ambiente. Sem nenhum habilitado, o Nominatim é usado
+// com um User-Agent que identifica esta CLI.
+func selectGeocoders(name string) ([]Geocoder, error) {
+	configured := configuredGeocoders()
+	if name == "" && len(configured) > 0 {
+		return configured, nil
+	}
+	for _, g := range configured {
+		if strings.EqualFold(g.Name(), name) {
+			return []Geocoder{g}, nil
+		}
+	}
+	switch strings.ToLower(name) {
+	case "", "nominatim":
+		return []Geocoder{nominatimGeocoder{userAgent: "cep/" + version}}, nil
+	case "google":
+		return nil, errors.New(tr("o geocoder Google precisa de GOOGLE_MAPS_API_KEY (ou tokens.google_maps na configuração)"))
+	}
+	return nil, errors.New(tr("geocoder desconhecido: %s (use google ou nominatim)", name))
+}
+
+// geocodeQuery monta o endereço em texto livre usado nas consultas.
+func geocodeQuery(addr Address) string {
+	var parts []string
+	for _, p := range []string{addr.Street, addr.Neighborhood, addr.City, addr.State} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	country := addr.Country
+	if country == "" {
+		country = "Brasil"
+	}
+	return strings.Join(append(parts, country), ", ")
+}
+
+// enrich preenche addr.Location com o primeiro geocoder que responder. Falhas
+// não são fatais: o endereço é devolvido como veio e os erros são retornados
+// apenas para diagnóstico.
+func enrich(ctx context.Context, addr Address, geocoders []Geocoder) (Address, []error) {
+	if addr.Location != nil {
+		return addr, nil
+	}
+	var errs []error
+	for _, g := range geocoders {
+		loc, err := g.Geocode(ctx, addr)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		addr.Location = loc
+		break
+	}
+	return addr, errs
+}
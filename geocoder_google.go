@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+)
+
+type GoogleGeocodeResponse struct {
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message"`
+	Results      []struct {
+		// AddressComponents só é usado na busca reversa.
+		AddressComponents []struct {
+			LongName  string   `json:"long_name"`
+			ShortName string   `json:"short_name"`
+			Types     []string `json:"types"`
+		} `json:"address_components"`
+		Geometry struct {
+			Location struct {
+				Lat float64 `json:"lat"`
+				Lng float64 `json:"lng"`
+			} `json:"location"`
+		} `json:"geometry"`
+	} `json:"results"`
+}
+
+// googleGeocoder usa a Geocoding API do Google; a chave vem de
+// GOOGLE_MAPS_API_KEY.
+type googleGeocoder struct {
+	key string
+}
+
+func (googleGeocoder) Name() string { return "Google" }
+
+func (g googleGeocoder) Geocode(ctx context.Context, addr Address) (*Location, error) {
+	q := url.Values{}
+	q.Set("address", geocodeQuery(addr))
+	q.Set("key", g.key)
+	if addr.CEP != "" {
+		country := addr.Country
+		if country == "" {
+			country = "BR"
+		}
+		q.Set("components", "postal_code:"+addr.CEP+"|country:"+country)
+	}
+	u := "https://maps.googleapis.com/maps/api/geocode/json?" + q.Encode()
+
+	var r GoogleGeocodeResponse
+	if err := getJSON(ctx, u, &r); err != nil {
+		// Não expõe a URL, que contém a chave da API
+		var ue *url.Error
+		if errors.As(err, &ue) {
+			err = ue.Err
+		}
+		return nil, fmt.Errorf("google: %w", err)
+	}
+	if r.Status != "OK" || len(r.Results) == 0 {
+		return nil, fmt.Errorf("google: %s %s", r.Status, r.ErrorMessage)
+	}
+
+	loc := r.Results[0].Geometry.Location
+	return &Location{Latitude: loc.Lat, Longitude: loc.Lng}, nil
+}
+
+func (g googleGeocoder) Reverse(ctx context.Context, loc Location) (Address, error) {
+	q := url.Values{}
+	q.Set("latlng", strconv.FormatFloat(loc.Latitude, 'f', -1, 64)+","+strconv.FormatFloat(loc.Longitude, 'f', -1, 64))
+	q.Set("result_type", "street_address|route|postal_code")
+	q.Set("language", "pt-BR")
+	q.Set("key", g.key)
+	u := "https://maps.googleapis.com/maps/api/geocode/json?" + q.Encode()
+
+	var r GoogleGeocodeResponse
+	if err := getJSON(ctx, u, &r); err != nil {
+		var ue *url.Error
+		if errors.As(err, &ue) {
+			err = ue.Err
+		}
+		return Address{}, fmt.Errorf("google: %w", err)
+	}
+	if r.Status != "OK" || len(r.Results) == 0 {
+		return Address{}, fmt.Errorf("google: %s %s", r.Status, r.ErrorMessage)
+	}
+
+	res := r.Results[0]
+	var addr Address
+	for _, c := range res.AddressComponents {
+		switch {
+		case slices.Contains(c.Types, "postal_code"):
+			addr.CEP = c.LongName
+		case slices.Contains(c.Types, "route"):
+			addr.Street = c.LongName
+		case slices.Contains(c.Types, "sublocality"):
+			addr.Neighborhood = c.LongName
+		case slices.Contains(c.Types, "administrative_area_level_2"):
+			addr.City = c.LongName
+		case slices.Contains(c.Types, "administrative_area_level_1"):
+			addr.State = c.ShortName
+		case slices.Contains(c.Types, "country"):
+			addr.Country = c.ShortName
+		}
+	}
+	p := res.Geometry.Location
+	addr.Location = &Location{Latitude: p.Lat, Longitude: p.Lng}
+	return addr, nil
+}
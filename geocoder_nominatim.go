@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type NominatimResult struct {
+	Lat         string `json:"lat"`
+	Lon         string `json:"lon"`
+	DisplayName string `json:"display_name"`
+	// Address e Error só vêm na busca reversa.
+	Address struct {
+		Road          string `json:"road"`
+		Suburb        string `json:"suburb"`
+		Neighbourhood string `json:"neighbourhood"`
+		City          string `json:"city"`
+		Town          string `json:"town"`
+		Village       string `json:"village"`
+		Municipality  string `json:"municipality"`
+		State         string `json:"state"`
+		StateCode     string `json:"ISO3166-2-lvl4"`
+		Postcode      string `json:"postcode"`
+		CountryCode   string `json:"country_code"`
+	} `json:"address"`
+	Error string `json:"error"`
+}
+
+// A política de uso do Nominatim permite no máximo uma requisição por
+// segundo e exige um User-Agent que identifique a aplicação.
+const nominatimInterval = time.Second
+
+var (
+	nominatimMu   sync.Mutex
+	nominatimLast time.Time
+)
+
+// nominatimWait bloqueia até que uma nova requisição seja permitida.
+func nominatimWait(ctx context.Context) error {
+	nominatimMu.Lock()
+	defer nominatimMu.Unlock()
+
+	if wait := time.Until(nominatimLast.Add(nominatimInterval)); wait > 0 {
+		t := time.NewTimer(wait)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	nominatimLast = time.Now()
+	return nil
+}
+
+// nominatimGeocoder usa o Nominatim do OpenStreetMap; o User-Agent vem de
+// NOMINATIM_USER_AGENT (ex.: "minha-app/1.0 (contato@exemplo.com)").
+type nominatimGeocoder struct {
+	userAgent string
+}
+
+func (nominatimGeocoder) Name() string { return "Nominatim" }
+
+func (n nominatimGeocoder) Geocode(ctx context.Context, addr Address) (*Location, error) {
+	q := url.Values{}
+	q.Set("format", "jsonv2")
+	q.Set("limit", "1")
+	if addr.Street != "" {
+		q.Set("street", addr.Street)
+	}
+	if addr.City != "" {
+		q.Set("city", addr.City)
+	}
+	if addr.State != "" {
+		q.Set("state", addr.State)
+	}
+	if addr.CEP != "" && addr.Street == "" {
+		q.Set("postalcode", addr.CEP)
+	}
+	country := addr.Country
+	if country == "" {
+		country = "BR"
+	}
+	q.Set("countrycodes", strings.ToLower(country))
+	u := "https://nominatim.openstreetmap.org/search?" + q.Encode()
+
+	if err := nominatimWait(ctx); err != nil {
+		return nil, err
+	}
+
+	var results []NominatimResult
+	header := http.Header{"User-Agent": {n.userAgent}}
+	if err := getJSONWithHeader(ctx, u, header, &results); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, errors.New("nominatim: " + tr("endereço não encontrado"))
+	}
+
+	r := results[0]
+	lat, err := strconv.ParseFloat(r.Lat, 64)
+	if err != nil {
+		return nil, err
+	}
+	lng, err := strconv.ParseFloat(r.Lon, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &Location{Latitude: lat, Longitude: lng, DisplayName: r.DisplayName}, nil
+}
+
+func (n nominatimGeocoder) Reverse(ctx context.Context, loc Location) (Address, error) {
+	q := url.Values{}
+	q.Set("format", "jsonv2")
+	q.Set("addressdetails", "1")
+	q.Set("lat", strconv.FormatFloat(loc.Latitude, 'f', -1, 64))
+	q.Set("lon", strconv.FormatFloat(loc.Longitude, 'f', -1, 64))
+	u := "https://nominatim.openstreetmap.org/reverse?" + q.Encode()
+
+	if err := nominatimWait(ctx); err != nil {
+		return Address{}, err
+	}
+
+	var r NominatimResult
+	header := http.Header{"User-Agent": {n.userAgent}}
+	if err := getJSONWithHeader(ctx, u, header, &r); err != nil {
+		return Address{}, err
+	}
+	if r.Error != "" {
+		return Address{}, errors.New("nominatim: " + r.Error)
+	}
+
+	a := r.Address
+	first := func(vs ...string) string {
+		for _, v := range vs {
+			if v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+	// ISO3166-2-lvl4 vem como "BR-SP"; sem ele, fica o nome do estado
+	state := a.State
+	if country, uf, ok := strings.Cut(a.StateCode, "-"); ok && strings.EqualFold(country, a.CountryCode) {
+		state = uf
+	}
+	addr := Address{
+		CEP:          a.Postcode,
+		Street:       a.Road,
+		Neighborhood: first(a.Suburb, a.Neighbourhood),
+		City:         first(a.City, a.Town, a.Village, a.Municipality),
+		State:        state,
+		Country:      strings.ToUpper(a.CountryCode),
+	}
+	if lat, err := strconv.ParseFloat(r.Lat, 64); err == nil {
+		if lng, err := strconv.ParseFloat(r.Lon, 64); err == nil {
+			addr.Location = &Location{Latitude: lat, Longitude: lng, DisplayName: r.DisplayName}
+		}
+	}
+	return addr, nil
+}
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// lang é o idioma das mensagens: "pt" (padrão) ou "en". Vem de --lang ou,
+// sem ele, de CEP_LANG, LC_ALL, LC_MESSAGES ou LANG.
+var lang = detectLang()
+
+var languages = []string{"pt", "en"}
+
+func detectLang() string {
+	for _, env := range []string{"CEP_LANG", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := strings.ToLower(os.Getenv(env))
+		switch {
+		case v == "":
+			continue
+		case strings.HasPrefix(v, "en"):
+			return "en"
+		default:
+			// C, POSIX e os demais idiomas ficam com o padrão
+			return "pt"
+		}
+	}
+	return "pt"
+}
+
+func setLang(v string) error {
+	for _, l := range languages {
+		if strings.EqualFold(v, l) {
+			lang = l
+			return nil
+		}
+	}
+	return errors.New(tr("idioma desconhecido: %s (use %s)", v, strings.Join(languages, ", ")))
+}
+
+// tr traduz a mensagem msg, escrita em português, para o idioma de --lang e
+// a formata com args, como fmt.Sprintf. Mensagens fora do catálogo saem em
+// português.
+func tr(msg string, args ...any) string {
+	if t, ok := catalog[lang][msg]; ok {
+		msg = t
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+var catalog = map[string]map[string]string{
+	"en": {
+		// Uso e subcomandos
+		"Uso: go run main.go [lookup] [--providers BrasilAPI,ViaCEP,...] [--country BR] [--strategy race] [--timeout 1s] [--format text] <cep>...": "Usage: go run main.go [lookup] [--providers BrasilAPI,ViaCEP,...] [--country BR] [--strategy race] [--timeout 1s] [--format text] <cep>...",
+		"Uso: go run main.go batch [opções] <arquivo>":                          "Usage: go run main.go batch [options] <file>",
+		"Uso: go run main.go bench [opções] [-n 20] <cep>":                      "Usage: go run main.go bench [options] [-n 20] <cep>",
+		"Uso: go run main.go cache warm [--concurrency 4] [--rate 5] <arquivo>": "Usage: go run main.go cache warm [--concurrency 4] [--rate 5] <file>",
+		"Uso: go run main.go completion bash|zsh|fish":                          "Usage: go run main.go completion bash|zsh|fish",
+		"\nSubcomandos:": "\nSubcommands:",
+		"\nUse go run main.go <subcomando> -h para ver as opções.":      "\nUse go run main.go <subcommand> -h to see its options.",
+		"lookup [opções] <cep>...":                                      "lookup [options] <cep>...",
+		"batch [opções] <arquivo>":                                      "batch [options] <file>",
+		"bench [opções] [-n 20] <cep>":                                  "bench [options] [-n 20] <cep>",
+		"repl [opções]":                                                 "repl [options]",
+		"tui [opções]":                                                  "tui [options]",
+		"cache stats|clear|warm [--concurrency 4] [--rate 5] <arquivo>": "cache stats|clear|warm [--concurrency 4] [--rate 5] <file>",
+		"consulta um ou mais CEPs":                                      "looks up one or more CEPs",
+		"consulta os CEPs de um arquivo, um por linha":                  "looks up the CEPs in a file, one per line",
+		"mede a latência e as vitórias de cada provider":                "measures each provider's latency and wins",
+		"modo interativo: consulta os CEPs digitados":                   "interactive mode: looks up the CEPs you type",
+		"interface de tela cheia para muitas consultas seguidas":        "full-screen interface for many lookups in a row",
+		"inspeciona, limpa ou aquece o cache":                           "inspects, clears or warms the cache",
+		"gera o script de completion do shell":                          "prints the shell completion script",
+		"mostra a versão":                                               "shows the version",
+		"mostra esta ajuda":                                             "shows this help",
+		"commit: %s\ndata do build: %s\n":                               "commit: %s\nbuild date: %s\n",
+		"desconhecido":                                                  "unknown",
+		"desconhecida":                                                  "unknown",
+		"formato desconhecido: %s (use %s)\n":                           "unknown format: %s (use %s)\n",
+		"formato desconhecido: %s (use %s)":                             "unknown format: %s (use %s)",
+		"shell desconhecido: %s (use %s)\n":                             "unknown shell: %s (use %s)\n",
+		"subcomando de cache desconhecido: %s\n":                        "unknown cache subcommand: %s\n",
+
+		// Resultado de uma consulta
+		"Resposta da %s:\n":                       "Response from %s:\n",
+		"Resposta da %s (cache):\n":               "Response from %s (cached):\n",
+		"Resposta da %s (cache desatualizado):\n": "Response from %s (stale cache):\n",
+		"%s (cache desatualizado)":                "%s (stale cache)",
+		"%s (cache)":                              "%s (cached)",
+		"Fonte":                                   "Source",
+		"CEP":                                     "CEP",
+		"Rua":                                     "Street",
+		"Complemento":                             "Complement",
+		"Bairro":                                  "Neighborhood",
+		"Cidade":                                  "City",
+		"Estado":                                  "State",
+		"País":                                    "Country",
+		"DDD":                                     "Area code",
+		"Código IBGE":                             "IBGE code",
+		"Código SIAFI":                            "SIAFI code",
+		"Código GIA":                              "GIA code",
+		"Fuso horário":                            "Time zone",
+		"Região":                                  "Region",
+		"Nome do estado":                          "State name",
+		"Uso: go run main.go distance [opções] <cep1> <cep2>": "Usage: go run main.go distance [options] <cep1> <cep2>",
+		"distance [opções] <cep1> <cep2>":                     "distance [options] <cep1> <cep2>",
+		"distância em linha reta entre dois CEPs":             "straight-line distance between two CEPs",
+		"Sem coordenadas para o CEP %s":                       "No coordinates for CEP %s",
+		"De:":                                                 "From:",
+		"Para:":                                               "To:",
+		"Distância:":                                          "Distance:",
+		"Uso: go run main.go search [opções] --uf SP --city \"São Paulo\" --street Paulista": "Usage: go run main.go search [options] --uf SP --city \"São Paulo\" --street Paulista",
+		"search [opções] <endereço> | --uf --city --street":                                  "search [options] <address> | --uf --city --street",
+		"procura os CEPs de um logradouro":                                                   "finds the CEPs of a street",
+		"Erro na busca: %v":                                                                  "Search failed: %v",
+		"Nenhum endereço encontrado":                                                         "No address found",
+		"página %d não existe: são %d páginas":                                               "page %d does not exist: there are %d pages",
+		"Página %d de %d; use --page para ver as outras\n":                                   "Page %d of %d; use --page to see the others\n",
+		"Uso: go run main.go validate [opções] --cep 01310100 [--street \"Av Paulista\"] [--city \"Sao Paulo\"] ...": "Usage: go run main.go validate [options] --cep 01310100 [--street \"Av Paulista\"] [--city \"Sao Paulo\"] ...",
+		"validate [opções] --cep <cep> [--street <rua>] [--city ...]":                                                "validate [options] --cep <cep> [--street <street>] [--city ...]",
+		"confere se um endereço informado bate com o do CEP":                                                         "checks whether a given address matches the CEP's",
+		"--min-score deve estar entre 0 e 1":                                                                         "--min-score must be between 0 and 1",
+		"confere":                                                                                                    "matches",
+		"não confere":                                                                                                "does not match",
+		"Resultado:":                                                                                                 "Result:",
+		"Uso: go run main.go near [opções] --lat -23.56 --lng -46.65":                                                "Usage: go run main.go near [options] --lat -23.56 --lng -46.65",
+		"near [opções] --lat <latitude> --lng <longitude>":                                                           "near [options] --lat <latitude> --lng <longitude>",
+		"o CEP mais próximo de um ponto (busca reversa)":                                                             "the CEP closest to a point (reverse geocoding)",
+		"coordenadas fora do intervalo: a latitude vai de -90 a 90 e a longitude de -180 a 180":                      "coordinates out of range: latitude goes from -90 to 90 and longitude from -180 to 180",
+		"nenhum dos geocoders faz busca reversa":                                                                     "none of the geocoders supports reverse geocoding",
+		"Erro na busca reversa (%s): %v":                                                                             "Reverse geocoding failed (%s): %v",
+		"Nenhum CEP encontrado perto de %g, %g":                                                                      "No CEP found near %g, %g",
+		"Confiança":                                                                                                  "Confidence",
+		"Buscando %s\n":                                                                                              "Searching %s\n",
+		"nº %d":                                                                                                      "no. %d",
+		"  ou: go run main.go search [opções] \"Av. Paulista 1578, Bela Vista, São Paulo SP\"": "   or: go run main.go search [options] \"Av. Paulista 1578, Bela Vista, São Paulo SP\"",
+		"não encontrei a UF no endereço (ex.: \"São Paulo SP\")":                               "could not find the state in the address (e.g. \"São Paulo SP\")",
+		"o endereço precisa ter pelo menos o logradouro e a cidade, separados por vírgula":     "the address needs at least the street and the city, separated by a comma",
+		"Uso: go run main.go serve [opções] [--addr localhost:8080]":                           "Usage: go run main.go serve [options] [--addr localhost:8080]",
+		"serve [opções] [--addr localhost:8080]":                                               "serve [options] [--addr localhost:8080]",
+		"API HTTP: GET /cep/{cep} devolve o endereço em JSON":                                  "HTTP API: GET /cep/{cep} returns the address as JSON",
+		"Servindo em http://%s\n":                                                              "Serving on http://%s\n",
+		"Encerrando: esperando as consultas em andamento por até %s\n":                         "Shutting down: waiting up to %s for in-flight lookups\n",
+		"Prazo de encerramento esgotado; conexões abertas foram fechadas: %v\n":                "Shutdown deadline exceeded; open connections were closed: %v\n",
+		"esperava nome:chave":            "expected name:key",
+		"chave de API sem nome ou vazia": "API key with no name or empty",
+		"as chaves de API de %s e %s são iguais; cada cliente precisa da sua":                 "the API keys of %s and %s are the same; each client needs its own",
+		"chave de API ausente ou inválida":                                                    "missing or invalid API key",
+		"Aviso: a API está aberta a outras máquinas sem chave de API; veja --api-keys-file\n": "Warning: the API is open to other machines without an API key; see --api-keys-file\n",
+		"find [opções] [--uf <UF>] [--city <cidade>]":                                         "find [options] [--uf <UF>] [--city <city>]",
+		"busca interativa de CEPs pelo nome da rua":                                           "interactive CEP finder by street name",
+		"UF":        "State",
+		"Buscando…": "Searching…",
+		"Informe a UF e pelo menos 3 letras da cidade e da rua": "Enter the state and at least 3 letters of the city and street",
+		"%d endereços": "%d addresses",
+		"Digite para buscar · Tab troca de campo · ↑/↓ escolhe · Enter copia o CEP · Esc sai": "Type to search · Tab switches field · ↑/↓ selects · Enter copies the CEP · Esc quits",
+		"a busca por endereço só está disponível para o Brasil":                               "address search is only available for Brazil",
+		"UF desconhecida: %s":                                             "unknown state: %s",
+		"--city deve ter pelo menos 3 letras":                             "--city must have at least 3 letters",
+		"--street deve ter pelo menos 3 letras":                           "--street must have at least 3 letters",
+		"--page deve ser pelo menos 1 e --per-page não pode ser negativo": "--page must be at least 1 and --per-page cannot be negative",
+		"Latitude":           "Latitude",
+		"Longitude":          "Longitude",
+		"Altitude":           "Altitude",
+		"Local":              "Place",
+		"Mapa":               "Map",
+		"Origem dos campos:": "Field sources:",
+		"--output só aceita planilhas .xlsx: %s":              "--output only accepts .xlsx spreadsheets: %s",
+		"Aviso: CEP %s sem coordenadas, omitido do GeoJSON\n": "Warning: CEP %s has no coordinates, left out of the GeoJSON\n",
+		"Resultados":                      "Results",
+		"Resumo":                          "Summary",
+		"Tempo (ms)":                      "Time (ms)",
+		"Erro":                            "Error",
+		"Desfecho":                        "Outcome",
+		"Encontrados":                     "Found",
+		"Do cache":                        "Cached",
+		"Não encontrados":                 "Not found",
+		"Inválidos":                       "Invalid",
+		"Com timeout":                     "Timed out",
+		"Com erro":                        "Failed",
+		"campo desconhecido: %s (use %s)": "unknown field: %s (use %s)",
+		"--fields não pode ser usado com --format %s": "--fields cannot be used with --format %s",
+		"--format %s não pode ser usado com --watch":  "--format %s cannot be used with --watch",
+		"Endereço":                           "Address",
+		"Erro na %s: %v":                     "Error from %s: %v",
+		"Erro na %s: %v\n":                   "Error from %s: %v\n",
+		"Timeout de %s excedido":             "Timeout of %s exceeded",
+		"CEP %s não encontrado":              "CEP %s not found",
+		"CEP %s inválido":                    "CEP %s is invalid",
+		"CEP %s inválido: %s":                "CEP %s is invalid: %s",
+		"vazio":                              "empty",
+		"deve ter só dígitos":                "must contain only digits",
+		"tem %d dígitos, deve ter 8":         "has %d digits, must have 8",
+		"Erro ao buscar CEP: %v":             "Error looking up CEP: %v",
+		"Erro ao escrever a saída: %v\n":     "Error writing output: %v\n",
+		"Aviso: geocodificação falhou: %v\n": "Warning: geocoding failed: %v\n",
+		"\n%d CEP(s): %d encontrados (%d do cache), %d não encontrados, %d inválidos, %d com timeout, %d com erro\n": "\n%d CEP(s): %d found (%d cached), %d not found, %d invalid, %d timed out, %d failed\n",
+		"\r[%s%s] %d/%d · %d ok · %d falhas · %d do cache · %.1f/s ":                                                 "\r[%s%s] %d/%d · %d ok · %d failed · %d cached · %.1f/s ",
+
+		// Dry-run
+		"Estratégia: %s, prazo total de %s\n":                       "Strategy: %s, overall timeout of %s\n",
+		"Cache: desligado\n":                                        "Cache: off\n",
+		"Cache: ligado (um CEP em cache não geraria requisições)\n": "Cache: on (a cached CEP would not trigger any request)\n",
+		"padrão": "default",
+		"%d. %s (timeout %s, %d nova(s) tentativa(s))\n":         "%d. %s (timeout %s, %d retry(ies))\n",
+		"   executaria %s (CEP via %s)\n":                        "   would run %s (CEP via %s)\n",
+		"   plugin Go: as requisições não podem ser previstas\n": "   Go plugin: its requests cannot be predicted\n",
+		"   consulta a base local, sem rede\n":                   "   looks up the local dataset, no network\n",
+		"   proxy inválido: %v\n":                                "   invalid proxy: %v\n",
+		"   via proxy %s\n":                                      "   via proxy %s\n",
+		"   sem proxy\n":                                         "   no proxy\n",
+
+		// Comparação
+		"Campo":                                                "Field",
+		"\n%d campo(s) com divergência (*)\n":                  "\n%d field(s) disagree (*)\n",
+		"\nTodos os providers concordam":                       "\nAll providers agree",
+		"[%s] %s passou a falhar: %v\n":                        "[%s] %s started failing: %v\n",
+		"[%s] %s voltou a responder\n":                         "[%s] %s is responding again\n",
+		"Consultas: %d (%d falharam)\n":                        "Lookups: %d (%d failed)\n",
+		"Latência: mín %s, média %s, p50 %s, p95 %s, máx %s\n": "Latency: min %s, avg %s, p50 %s, p95 %s, max %s\n",
+		"  %s: %d vitória(s)\n":                                "  %s: %d win(s)\n",
+
+		// Cache
+		"Acertos: %d\nFalhas: %d\nDesatualizados servidos: %d\n":     "Hits: %d\nMisses: %d\nStale served: %d\n",
+		"Taxa de acerto: %.1f%%\n":                                   "Hit rate: %.1f%%\n",
+		"Desde: %s\n":                                                "Since: %s\n",
+		"Erro ao ler o cache: %v\n":                                  "Error reading the cache: %v\n",
+		"Entradas: %d\nExpiradas: %d\nNegativas: %d\n":               "Entries: %d\nExpired: %d\nNegative: %d\n",
+		"O cache configurado não pode ser limpo":                     "The configured cache cannot be cleared",
+		"Erro ao limpar o cache: %v\n":                               "Error clearing the cache: %v\n",
+		"Cache limpo":                                                "Cache cleared",
+		"Falha ao aquecer %s: %v\n":                                  "Failed to warm %s: %v\n",
+		"%d CEP(s) aquecidos, %d já estavam em cache, %d falharam\n": "%d CEP(s) warmed, %d already cached, %d failed\n",
+		"Não há cache para aquecer (--no-cache)":                     "There is no cache to warm (--no-cache)",
+
+		// REPL e TUI
+		"Digite um ou mais CEPs, ou :help para ver os comandos.": "Type one or more CEPs, or :help to see the commands.",
+		"Aviso: não foi possível salvar o histórico: %v\n":       "Warning: could not save the history: %v\n",
+		"Histórico sem %s\n":                                                              "No %s in history\n",
+		"Comando desconhecido: :%s (use :help)\n":                                         "Unknown command: :%s (use :help)\n",
+		"<cep> [<cep>...]          consulta os CEPs":                                      "<cep> [<cep>...]          looks up the CEPs",
+		":providers                lista os providers em uso":                             ":providers                lists the providers in use",
+		":providers +Nome -Nome    liga ou desliga providers":                             ":providers +Name -Name    turns providers on or off",
+		":providers Nome,Nome      usa só os providers informados":                        ":providers Name,Name      uses only the given providers",
+		":history                  mostra o histórico; !n repete a linha n e !! a última": ":history                  shows the history; !n repeats line n and !! the last one",
+		":quit                     sai":                                                   ":quit                     exits",
+		"  vencedor":                                                                      "  winner",
+		"  (última: %s)":                                                                  "  (last: %s)",
+		"cancelado após %s":                                                               "canceled after %s",
+		"Resultado (%s)":                                                                  "Result (%s)",
+		"%s copiado":                                                                      "%s copied",
+		"Enter consulta · Tab alterna entre busca e resultado · ↑/↓ escolhe · Enter/c copia o campo · a copia tudo · Esc sai": "Enter looks up · Tab switches between search and result · ↑/↓ selects · Enter/c copies the field · a copies everything · Esc quits",
+
+		// Erros
+		"CEP não encontrado":                                       "CEP not found",
+		"CEP inválido":                                             "invalid CEP",
+		"tempo esgotado":                                           "timed out",
+		"todos os providers falharam":                              "all providers failed",
+		"circuito aberto":                                          "circuit open",
+		"aguardando Retry-After":                                   "waiting for Retry-After",
+		"dry-run: requisição não enviada":                          "dry-run: request not sent",
+		"resposta HTTP %s":                                         "HTTP response %s",
+		"provider desconhecido: %s":                                "unknown provider: %s",
+		"ao menos um provider precisa ficar ligado":                "at least one provider must stay enabled",
+		"estratégia desconhecida: %s (use %s)":                     "unknown strategy: %s (use %s)",
+		"timeout por provider inválido: %q (use Nome=duração)":     "invalid per-provider timeout: %q (use Name=duration)",
+		"timeout da %s":                                            "timeout of %s",
+		"valor inválido %q para %s em %s: %v\n":                    "invalid value %q for %s in %s: %v\n",
+		"--normalize desconhecido: %s (use ascii, upper ou title)": "unknown --normalize: %s (use ascii, upper or title)",
+		"--street-type desconhecido: %s (use expand ou abbrev)":    "unknown --street-type: %s (use expand or abbrev)",
+		"nomes desconhecidos: %s (use json ou camel)":              "unknown names: %s (use json or camel)",
+		"mapa desconhecido: %s (use google ou osm)":                "unknown map: %s (use google or osm)",
+		"idioma desconhecido: %s (use %s)":                         "unknown language: %s (use %s)",
+		"geocoder desconhecido: %s (use google ou nominatim)":      "unknown geocoder: %s (use google or nominatim)",
+		"o geocoder Google precisa de GOOGLE_MAPS_API_KEY (ou tokens.google_maps na configuração)": "the Google geocoder needs GOOGLE_MAPS_API_KEY (or tokens.google_maps in the configuration)",
+		"endereço não encontrado":                                       "address not found",
+		"quórum não atingido: %d de %d votos concordam (mínimo %d)":     "quorum not reached: %d of %d votes agree (minimum %d)",
+		"\n  %d voto(s) [%s]: %s, %s - %s":                              "\n  %d vote(s) [%s]: %s, %s - %s",
+		"\n  sem resposta: %v":                                          "\n  no answer: %v",
+		"Aviso: não foi possível salvar as latências: %v\n":             "Warning: could not save the latencies: %v\n",
+		"Aviso: não foi possível salvar as estatísticas do cache: %v\n": "Warning: could not save the cache statistics: %v\n",
+		"plugin de cache %s":                                            "cache plugin %s",
+		"plugin de cache %s: NewCache deve ter a assinatura func() any": "cache plugin %s: NewCache must have the signature func() any",
+		"plugin de cache %s: NewCache não retornou um cache válido":     "cache plugin %s: NewCache did not return a valid cache",
+		"plugin %s: NewProvider deve ter a assinatura func() any":       "plugin %s: NewProvider must have the signature func() any",
+		"plugin %s: NewProvider não retornou um provider válido":        "plugin %s: NewProvider did not return a valid provider",
+		"plugin sem nome na configuração":                               "plugin without a name in the configuration",
+		"plugin %s: command é obrigatório":                              "plugin %s: command is required",
+		"plugin %s: input deve ser stdin ou argv":                       "plugin %s: input must be stdin or argv",
+		"provider sem nome na configuração":                             "provider without a name in the configuration",
+		"provider %s: url deve conter {cep}":                            "provider %s: url must contain {cep}",
+		"provider %s: campo desconhecido %q":                            "provider %s: unknown field %q",
+
+		// Opções (-h)
+		"Opções de %s:\n":                                 "Options of %s:\n",
+		"estratégia de consulta":                          "lookup strategy",
+		"arquivo de configuração":                         "configuration file",
+		"formato da saída":                                "output format",
+		"providers separados por vírgula (padrão: todos)": "comma-separated providers (default: all)",
+		"prazo total da consulta (padrão: o maior timeout por provider, ou 1s)":                                         "total lookup deadline (default: the largest per-provider timeout, or 1s)",
+		"país do código postal (ISO 3166-1 alfa-2)":                                                                     "country of the postal code (ISO 3166-1 alpha-2)",
+		"espera antes de acionar o próximo provider na estratégia hedge":                                                "wait before calling the next provider in the hedge strategy",
+		"mínimo de providers que devem concordar (implica --strategy quorum)":                                           "minimum number of providers that must agree (implies --strategy quorum)",
+		"provider preferido; sua resposta é usada se chegar dentro de --prefer-grace (implica --strategy prefer)":       "preferred provider; its answer is used if it arrives within --prefer-grace (implies --strategy prefer)",
+		"janela de espera pelo provider de --prefer":                                                                    "how long to wait for the --prefer provider",
+		"prazo de cada provider na estratégia fallback (padrão: divide o timeout entre eles)":                           "deadline of each provider in the fallback strategy (default: splits the timeout among them)",
+		"novas tentativas por provider em falhas transitórias de rede e respostas 5xx":                                  "retries per provider on transient network failures and 5xx responses",
+		"espera base entre tentativas (dobra a cada uma)":                                                               "base wait between retries (doubles each time)",
+		"máximo de novas tentativas somando todos os providers e CEPs (0 = sem limite)":                                 "maximum retries across all providers and CEPs (0 = no limit)",
+		"timeout por provider, ex.: BrasilAPI=700ms,ViaCEP=1s":                                                          "per-provider timeout, e.g. BrasilAPI=700ms,ViaCEP=1s",
+		"deriva o timeout de cada provider do p95 das latências recentes":                                               "derives each provider's timeout from the p95 of recent latencies",
+		"máximo de CEPs no cache em memória":                                                                            "maximum number of CEPs in the in-memory cache",
+		"validade dos endereços em cache":                                                                               "lifetime of cached addresses",
+		"idade a partir da qual o endereço em cache é atualizado em segundo plano (0 desliga)":                          "age after which a cached address is refreshed in the background (0 disables)",
+		"validade em cache dos CEPs não encontrados (0 desliga)":                                                        "lifetime of cached not-found CEPs (0 disables)",
+		"não usa o cache (nem em memória nem em disco)":                                                                 "does not use the cache (neither in memory nor on disk)",
+		"atalho para --strategy merge":                                                                                  "shortcut for --strategy merge",
+		"diretório com plugins Go (.so) de providers":                                                                   "directory with Go provider plugins (.so)",
+		"não colore a saída (o mesmo que definir NO_COLOR)":                                                             "does not color the output (the same as setting NO_COLOR)",
+		"completa as coordenadas dos endereços com um geocoder; falhas só geram avisos":                                 "fills in address coordinates with a geocoder; failures only produce warnings",
+		"geocoder do --geo: google ou nominatim (padrão: os configurados, ou nominatim)":                                "geocoder for --geo: google or nominatim (default: the configured ones, or nominatim)",
+		"padroniza o tipo de logradouro: expand (Av. → Avenida) ou abbrev (Avenida → Av.)":                              "standardizes the street type: expand (Av. → Avenida) or abbrev (Avenida → Av.)",
+		"transforma os textos do endereço: ascii (sem acentos), upper ou title; combine com vírgulas, ex.: ascii,upper": "transforms the address texts: ascii (no accents), upper or title; combine with commas, e.g. ascii,upper",
+		"mostra as requisições que seriam feitas, na ordem dos providers, sem acessar a rede":                           "shows the requests that would be made, in provider order, without touching the network",
+		"mostra na saída de erro a resposta e a latência de cada provider, inclusive dos que perdem a corrida":          "shows on standard error the answer and latency of every provider, including those that lose the race",
+		"atalho para --debug": "shortcut for --debug",
+		"idioma das mensagens: pt ou en (padrão: do ambiente)": "message language: pt or en (default: from the environment)",
+		"número de consultas":                                            "number of lookups",
+		"número máximo de consultas simultâneas":                         "maximum number of concurrent lookups",
+		"máximo de consultas por segundo (0 desativa o limite)":          "maximum lookups per second (0 disables the limit)",
+		"UF inicial da busca, ex.: SP":                                   "initial state of the search, e.g. SP",
+		"cidade inicial da busca":                                        "initial city of the search",
+		"consulta todos os providers e compara as respostas lado a lado": "queries every provider and compares the answers side by side",
+		"grava os resultados numa planilha (.xlsx), com uma aba de resumo, em vez de escrevê-los na saída":      "writes the results to a spreadsheet (.xlsx), with a summary sheet, instead of the output",
+		"arquivo com um CEP por linha, consultados além dos argumentos":                                         "file with one CEP per line, looked up in addition to the arguments",
+		"máximo de CEPs consultados ao mesmo tempo":                                                             "maximum number of CEPs looked up at the same time",
+		"não mostra a barra de progresso do --input":                                                            "does not show the --input progress bar",
+		"repete a consulta neste intervalo, mostrando só as mudanças (ex.: 30s)":                                "repeats the lookup at this interval, showing only changes (e.g. 30s)",
+		"atalho para --format oneline: o endereço numa linha, como em formulários de envio":                     "shortcut for --format oneline: the address on one line, as in shipping forms",
+		"nome do destinatário no formato label":                                                                 "recipient name for the label format",
+		"campos do endereço a escrever, separados por vírgula, ex.: city,state":                                 "comma-separated address fields to write, e.g. city,state",
+		"modelo text/template de cada resultado, ex.: '{{.Street}}, {{.City}}-{{.State}}' (substitui --format)": "text/template for each result, e.g. '{{.Street}}, {{.City}}-{{.State}}' (replaces --format)",
+		"escreve só o resultado no formato escolhido, sem cabeçalhos; erros vão para a saída de erro":           "writes only the result in the chosen format, without headers; errors go to standard error",
+		"nomes dos elementos do formato xml: json (elapsed_ms) ou camel (elapsedMs, como em esquemas XSD)":      "element names of the xml format: json (elapsed_ms) or camel (elapsedMs, as in XSD schemas)",
+		"acrescenta à saída de texto um link do endereço no mapa: google ou osm":                                "adds a map link of the address to the text output: google or osm",
+		"latitude do ponto, ex.: -23.56":                                                                        "latitude of the point, e.g. -23.56",
+		"longitude do ponto, ex.: -46.65":                                                                       "longitude of the point, e.g. -46.65",
+		"UF do endereço, ex.: SP":                                                                               "state of the address, e.g. SP",
+		"cidade do endereço (pelo menos 3 letras)":                                                              "city of the address (at least 3 letters)",
+		"logradouro, ou parte dele (pelo menos 3 letras)":                                                       "street, or part of it (at least 3 letters)",
+		"página dos resultados a mostrar":                                                                       "page of results to show",
+		"resultados por página; 0 mostra todos":                                                                 "results per page; 0 shows all",
+		"endereço em que o servidor escuta, ex.: :8080 para todas as interfaces":                                "address the server listens on, e.g. :8080 for all interfaces",
+		"o /readyz exige que algum provider tenha respondido neste intervalo":                                   "/readyz requires some provider to have answered within this interval",
+		"CEP consultado pelo /readyz quando nenhum provider respondeu recentemente":                             "CEP looked up by /readyz when no provider answered recently",
+		"ao receber SIGTERM ou SIGINT, prazo para terminar as consultas em andamento":                           "on SIGTERM or SIGINT, deadline to finish the lookups in progress",
+		"origens que podem chamar a API pelo navegador, separadas por vírgula (ex.: https://loja.com.br,https://*.loja.com.br ou *); vazio desliga o CORS": "comma-separated origins allowed to call the API from a browser (e.g. https://loja.com.br,https://*.loja.com.br or *); empty disables CORS",
+		"métodos permitidos nas chamadas CORS, separados por vírgula":                                                                                      "comma-separated methods allowed in CORS calls",
+		"cabeçalhos permitidos nas chamadas CORS, separados por vírgula":                                                                                   "comma-separated headers allowed in CORS calls",
+		"arquivo com as chaves de API aceitas, uma \"nome:chave\" por linha; soma-se a server.api_keys do config.yaml":                                     "file with the accepted API keys, one \"name:key\" per line; added to server.api_keys from config.yaml",
+		"ao sair, grava as métricas finais neste arquivo, no formato do Prometheus":                                                                        "on exit, writes the final metrics to this file, in Prometheus format",
+		"CEP a validar": "CEP to validate",
+		"logradouro informado, ex.: \"Av Paulista\"":      "given street, e.g. \"Av Paulista\"",
+		"bairro informado":                                "given neighborhood",
+		"cidade informada":                                "given city",
+		"UF ou nome do estado informado":                  "given state code or name",
+		"nota mínima, de 0 a 1, para o endereço conferir": "minimum score, from 0 to 1, for the address to match",
+	},
+}
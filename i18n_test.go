@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// withLang troca o idioma das mensagens durante o teste.
+func withLang(t *testing.T, l string) {
+	old := lang
+	lang = l
+	t.Cleanup(func() { lang = old })
+}
+
+func TestSentinelsTranslated(t *testing.T) {
+	withLang(t, "en")
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{ErrNotFound, "CEP not found"},
+		{ErrInvalidCEP, "invalid CEP"},
+		{ErrTimeout, "timed out"},
+		{ErrAllProvidersFailed, "all providers failed"},
+		{fmt.Errorf("ViaCEP: %w", errCircuitOpen), "ViaCEP: circuit open"},
+		{&invalidCEPError{tr("vazio")}, "invalid CEP: empty"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Error() = %q, quero %q", got, tt.want)
+		}
+	}
+	if !errors.Is(fmt.Errorf("BrasilAPI: %w", ErrNotFound), ErrNotFound) || errors.Is(ErrNotFound, ErrInvalidCEP) {
+		t.Error("os sentinelas perderam a identidade para errors.Is")
+	}
+}
+
+// Toda opção dos subcomandos precisa de tradução para o -h em inglês.
+func TestFlagUsagesTranslated(t *testing.T) {
+	for _, cmd := range commands {
+		if cmd.flags == nil {
+			continue
+		}
+		fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+		cmd.flags(fs)
+		fs.VisitAll(func(f *flag.Flag) {
+			head, _, _ := strings.Cut(f.Usage, ": ")
+			if _, ok := catalog["en"][f.Usage]; !ok && catalog["en"][head] == "" {
+				t.Errorf("%s --%s: sem tradução para %q", cmd.name, f.Name, f.Usage)
+			}
+		})
+	}
+}
+
+func TestPrintFlagUsage(t *testing.T) {
+	withLang(t, "en")
+	fs := flag.NewFlagSet("cep lookup", flag.ContinueOnError)
+	registerLookupFlags(fs)
+	var out bytes.Buffer
+	fs.SetOutput(&out)
+	printFlagUsage(fs)
+	for _, want := range []string{"Options of cep lookup:", "configuration file (default ", "output format: csv, geojson, json", "maximum number of CEPs looked up at the same time"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("ajuda sem %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// latencySamples é quantas latências recentes são guardadas por provider.
+	latencySamples = 100
+	// latencyMinSamples é o mínimo de amostras para derivar um timeout.
+	latencyMinSamples = 10
+
+	adaptiveFactor     = 1.5
+	adaptiveMinTimeout = 300 * time.Millisecond
+	adaptiveMaxTimeout = 5 * time.Second
+)
+
+// latencyTracker guarda as latências recentes de cada provider, persistidas
+// entre execuções em latency.json no diretório de cache do usuário.
+type latencyTracker struct {
+	path string
+
+	mu      sync.Mutex
+	samples map[string][]time.Duration
+	dirty   bool
+}
+
+// defaultLatencyPath retorna ~/.cache/cep/latency.json (ou equivalente do SO).
+func defaultLatencyPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "cep", "latency.json")
+}
+
+// loadLatencyTracker lê as latências salvas em path; um arquivo ausente ou
+// corrompido resulta num histórico vazio.
+func loadLatencyTracker(path string) *latencyTracker {
+	t := &latencyTracker{path: path, samples: map[string][]time.Duration{}}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &t.samples)
+	}
+	return t
+}
+
+// record adiciona uma amostra de latência do provider name.
+func (t *latencyTracker) record(name string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := append(t.samples[name], d)
+	if len(s) > latencySamples {
+		s = s[len(s)-latencySamples:]
+	}
+	t.samples[name] = s
+	t.dirty = true
+}
+
+// p95 devolve o percentil 95 das latências do provider name, ou zero se não
+// houver amostras suficientes.
+func (t *latencyTracker) p95(name string) time.Duration {
+	t.mu.Lock()
+	s := slices.Clone(t.samples[name])
+	t.mu.Unlock()
+
+	if len(s) < latencyMinSamples {
+		return 0
+	}
+	slices.Sort(s)
+	return s[(len(s)*95+99)/100-1]
+}
+
+// timeout deriva o timeout do provider name a partir do seu p95, ou zero se
+// ainda não houver histórico.
+func (t *latencyTracker) timeout(name string) time.Duration {
+	p := t.p95(name)
+	if p == 0 {
+		return 0
+	}
+	d := time.Duration(float64(p) * adaptiveFactor)
+	return min(max(d, adaptiveMinTimeout), adaptiveMaxTimeout)
+}
+
+// save grava as latências em disco, se houver novas amostras.
+func (t *latencyTracker) save() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.dirty || t.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(t.samples)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return err
+	}
+	tmp := t.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, t.path); err != nil {
+		return errors.Join(err, os.Remove(tmp))
+	}
+	t.dirty = false
+	return nil
+}
+
+type latencyProvider struct {
+	Provider
+	tracker *latencyTracker
+}
+
+// withLatency envolve p para registrar a latência das consultas bem-sucedidas.
+func withLatency(p Provider, tracker *latencyTracker) Provider {
+	return latencyProvider{Provider: p, tracker: tracker}
+}
+
+func (l latencyProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	start := time.Now()
+	addr, err := l.Provider.Fetch(ctx, cep)
+	if err == nil {
+		l.tracker.record(l.Name(), time.Since(start))
+	}
+	return addr, err
+}
@@ -0,0 +1,485 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// lookupFlags são as opções dos subcomandos lookup e batch.
+type lookupFlags struct {
+	opts        *options
+	compare     bool
+	format      string
+	input       string
+	concurrency int
+	watch       time.Duration
+	noProgress  bool
+	quiet       bool
+	template    string
+	oneline     bool
+	fields      string
+	output      string
+	// formatSet indica que --format foi informado; sem ele, várias consultas
+	// saem em tabela.
+	formatSet bool
+	// flush termina a saída dos formatos que só escrevem no fim (table).
+	flush func() error
+	// scored indica resultados com Confidence (busca por texto livre), que
+	// ganham uma coluna na tabela.
+	scored bool
+}
+
+func registerLookupFlags(fs *flag.FlagSet) *lookupFlags {
+	f := &lookupFlags{opts: registerOptions(fs)}
+	fs.BoolVar(&f.compare, "compare", false, "consulta todos os providers e compara as respostas lado a lado")
+	f.registerOutputFlags(fs)
+	fs.StringVar(&f.output, "output", "", "grava os resultados numa planilha (.xlsx), com uma aba de resumo, em vez de escrevê-los na saída")
+	fs.StringVar(&f.input, "input", "", "arquivo com um CEP por linha, consultados além dos argumentos")
+	fs.IntVar(&f.concurrency, "concurrency", 8, "máximo de CEPs consultados ao mesmo tempo")
+	fs.BoolVar(&f.noProgress, "no-progress", false, "não mostra a barra de progresso do --input")
+	fs.DurationVar(&f.watch, "watch", 0, "repete a consulta neste intervalo, mostrando só as mudanças (ex.: 30s)")
+	return f
+}
+
+// registerOutputFlags declara em fs as opções do formato da saída, comuns a
+// lookup e search.
+func (f *lookupFlags) registerOutputFlags(fs *flag.FlagSet) {
+	fs.StringVar(&f.format, "format", "text", "formato da saída: "+strings.Join(formatNames(), ", "))
+	fs.Func("xml-names", "nomes dos elementos do formato xml: json (elapsed_ms) ou camel (elapsedMs, como em esquemas XSD)", setXMLNames)
+	fs.BoolVar(&f.oneline, "oneline", false, "atalho para --format oneline: o endereço numa linha, como em formulários de envio")
+	fs.Func("map", "acrescenta à saída de texto um link do endereço no mapa: google ou osm", setMapLinks)
+	fs.StringVar(&labelRecipient, "recipient", "", "nome do destinatário no formato label")
+	fs.StringVar(&f.fields, "fields", "", "campos do endereço a escrever, separados por vírgula, ex.: city,state")
+	fs.StringVar(&f.template, "template", "", "modelo text/template de cada resultado, ex.: '{{.Street}}, {{.City}}-{{.State}}' (substitui --format)")
+	fs.BoolVar(&f.quiet, "quiet", false, "escreve só o resultado no formato escolhido, sem cabeçalhos; erros vão para a saída de erro")
+}
+
+// runLookup consulta os CEPs informados e mostra os resultados na ordem dos
+// argumentos.
+func runLookup(args []string) int {
+	fs := flag.NewFlagSet("cep lookup", flag.ExitOnError)
+	f := registerLookupFlags(fs)
+	parseFlags(fs, args)
+	f.formatSet = flagSet(fs, "format")
+
+	if fs.NArg() == 0 && f.input == "" {
+		if !stdinIsPipe() {
+			printUsage(os.Stdout)
+			return exitUsage
+		}
+		// Sem argumentos e com a entrada redirecionada, lê os CEPs dela; o
+		// formato padrão passa a ser uma linha por resultado
+		if !f.formatSet {
+			f.format = "tsv"
+		}
+		return f.stream(os.Stdin)
+	}
+	return f.run(fs.Args())
+}
+
+// stdinIsPipe informa se a entrada padrão vem de um pipe ou arquivo, e não
+// de um terminal.
+func stdinIsPipe() bool {
+	return !isTerminal(os.Stdin)
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// flagSet informa se a opção name foi passada explicitamente em fs.
+func flagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// runBatch consulta os CEPs de um arquivo; equivale a lookup --input.
+func runBatch(args []string) int {
+	fs := flag.NewFlagSet("cep batch", flag.ExitOnError)
+	f := registerLookupFlags(fs)
+	parseFlags(fs, args)
+	f.formatSet = flagSet(fs, "format")
+
+	if fs.NArg() != 1 {
+		fmt.Println(tr("Uso: go run main.go batch [opções] <arquivo>"))
+		return exitUsage
+	}
+	f.input = fs.Arg(0)
+	return f.run(nil)
+}
+
+// formatter devolve o formatter de --format; com --quiet, o texto sai sem o
+// cabeçalho "Resposta da ...". Nos formatos com cabeçalho, ele é escrito
+// antes do primeiro resultado.
+func (f *lookupFlags) formatter() (formatter, error) {
+	if f.oneline {
+		f.format = "oneline"
+	}
+	if f.template != "" {
+		return templateFormatter(f.template)
+	}
+	// Estes formatos só escrevem no fim, e o --watch nunca termina
+	if (f.format == "table" || f.format == "geojson") && f.watch > 0 {
+		return nil, errors.New(tr("--format %s não pode ser usado com --watch", f.format))
+	}
+	if f.fields != "" {
+		names, err := parseFields(f.fields)
+		if err != nil {
+			return nil, err
+		}
+		write, flush, err := fieldsFormatter(f.format, names)
+		f.flush = flush
+		return write, err
+	}
+	if f.quiet && f.format == "text" {
+		return writeTextBody, nil
+	}
+	if f.format == "geojson" {
+		var write formatter
+		write, f.flush = geojsonFormatter()
+		return write, nil
+	}
+	if f.format == "table" {
+		header := make([]string, len(tableColumns))
+		for i, c := range tableColumns {
+			header[i] = tr(c)
+		}
+		row := tableRow
+		if mapLinks != "" {
+			header = append(header, tr("Mapa"))
+			row = func(res APIResult) []string { return append(tableRow(res), mapURL(mapLinks, res.Addr)) }
+		}
+		if f.scored {
+			header = append(header, tr("Confiança"))
+			withMap := row
+			row = func(res APIResult) []string { return append(withMap(res), fmt.Sprintf("%.0f%%", res.Confidence*100)) }
+		}
+		var write formatter
+		write, f.flush = tableFormatter(header, row)
+		return write, nil
+	}
+	write, ok := formats[f.format]
+	if !ok {
+		return nil, errors.New(tr("formato desconhecido: %s (use %s)", f.format, strings.Join(formatNames(), ", ")))
+	}
+	if header, has := formatHeaders[f.format]; has {
+		var once sync.Once
+		return func(w io.Writer, res APIResult) error {
+			var err error
+			once.Do(func() { err = header(w) })
+			if err != nil {
+				return err
+			}
+			return write(w, res)
+		}, nil
+	}
+	return write, nil
+}
+
+// oneLine informa se a saída tem uma linha por resultado, sem linha em
+// branco entre eles.
+func (f *lookupFlags) oneLine() bool {
+	return f.template != "" || f.format == "table" || f.format == "geojson" || lineFormats[f.format]
+}
+
+// run consulta ceps, mais os de --input, e escreve os resultados em ordem.
+// Com --input, um resumo dos sucessos e falhas vai para a saída de erro.
+func (f *lookupFlags) run(ceps []string) int {
+	// Os argumentos são conferidos antes de qualquer consulta; as linhas
+	// inválidas de --input só entram no resumo
+	if !checkCEPs(os.Stdout, f.opts.country, ceps) {
+		return exitInvalidCEP
+	}
+	if f.input != "" {
+		file, err := os.Open(f.input)
+		if err != nil {
+			fmt.Println(err)
+			return exitUsage
+		}
+		more, err := readCEPs(file)
+		file.Close()
+		if err != nil {
+			fmt.Println(err)
+			return exitUsage
+		}
+		ceps = append(ceps, more...)
+	}
+	if len(ceps) > 1 && !f.formatSet && !f.oneline && f.template == "" && !f.quiet && !f.compare && f.watch == 0 {
+		f.format = "table"
+	}
+	write, err := f.formatter()
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	if f.output != "" {
+		if err := checkOutputPath(f.output); err != nil {
+			fmt.Println(err)
+			return exitUsage
+		}
+	}
+
+	app, err := f.setup()
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	defer app.Close()
+
+	if f.opts.dryRun {
+		printDryRun(os.Stdout, app, ceps)
+		return exitOK
+	}
+	if f.watch > 0 {
+		return f.watchLoop(app, ceps, write)
+	}
+	if f.output != "" {
+		return f.runOutput(app, ceps)
+	}
+	if f.format == "ndjson" && !f.compare {
+		return f.runUnordered(app, ceps, write)
+	}
+	var lookups []Lookup
+	if f.input != "" && !f.noProgress && isTerminal(os.Stderr) {
+		lookups = resolveWithProgress(os.Stderr, app.resolver, ceps, f.concurrency)
+	} else {
+		lookups = app.resolver.ResolveAll(context.Background(), ceps, f.concurrency)
+	}
+	errw := io.Writer(os.Stdout)
+	if f.quiet {
+		errw = os.Stderr
+	}
+	code := exitOK
+	for i, lookup := range lookups {
+		if i > 0 && !f.quiet && !f.oneLine() {
+			fmt.Println()
+		}
+		if f.compare {
+			printComparison(os.Stdout, lookup.Results)
+		} else {
+			printLookup(os.Stdout, errw, app.resolver, ceps[i], lookup, write)
+		}
+		// O código de saída é o da primeira falha
+		if c := lookupExitCode(lookup); code == exitOK {
+			code = c
+		}
+	}
+	if f.flush != nil {
+		f.flush()
+	}
+	if f.input != "" {
+		printSummary(os.Stderr, lookups)
+	}
+	return code
+}
+
+// runOutput consulta ceps e grava os resultados na planilha de --output; o
+// resumo vai para a saída de erro.
+func (f *lookupFlags) runOutput(a *app, ceps []string) int {
+	var lookups []Lookup
+	if !f.noProgress && isTerminal(os.Stderr) {
+		lookups = resolveWithProgress(os.Stderr, a.resolver, ceps, f.concurrency)
+	} else {
+		lookups = a.resolver.ResolveAll(context.Background(), ceps, f.concurrency)
+	}
+	if err := writeXLSX(f.output, ceps, lookups); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	printSummary(os.Stderr, lookups)
+	code := exitOK
+	for _, lookup := range lookups {
+		if c := lookupExitCode(lookup); code == exitOK {
+			code = c
+		}
+	}
+	return code
+}
+
+// runUnordered consulta ceps e escreve cada resultado assim que ele fica
+// pronto, sem esperar os anteriores; serve ao ndjson, em que cada linha traz o
+// próprio CEP. As mensagens de erro vão para a saída de erro, prefixadas pelo
+// CEP.
+func (f *lookupFlags) runUnordered(a *app, ceps []string, write formatter) int {
+	var mu sync.Mutex
+	lookups := make([]Lookup, len(ceps))
+	code := exitOK
+	a.resolver.ResolveEach(context.Background(), ceps, f.concurrency, func(i int, lookup Lookup) {
+		mu.Lock()
+		defer mu.Unlock()
+		lookups[i] = lookup
+		var errs bytes.Buffer
+		printLookup(os.Stdout, &errs, a.resolver, ceps[i], lookup, write)
+		for _, line := range strings.Split(strings.TrimSpace(errs.String()), "\n") {
+			if line != "" {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", ceps[i], line)
+			}
+		}
+		if c := lookupExitCode(lookup); code == exitOK {
+			code = c
+		}
+	})
+	if f.input != "" {
+		printSummary(os.Stderr, lookups)
+	}
+	return code
+}
+
+func (f *lookupFlags) setup() (*app, error) {
+	opts := f.opts
+	if f.compare {
+		opts.strategy, opts.merge, opts.quorum, opts.prefer = "all", false, 0, ""
+		// A comparação precisa da resposta atual de cada provider
+		opts.noCache = true
+	}
+	if f.watch > 0 {
+		// Cada rodada precisa da resposta atual dos providers
+		opts.noCache = true
+	}
+	return opts.setup()
+}
+
+// stream consulta os CEPs lidos de r, um por linha, escrevendo cada
+// resultado assim que ele e os anteriores estiverem prontos, na ordem da
+// entrada. As mensagens de erro vão para a saída de erro, prefixadas pelo CEP.
+func (f *lookupFlags) stream(r io.Reader) int {
+	write, err := f.formatter()
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	app, err := f.setup()
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	defer app.Close()
+
+	type pending struct {
+		cep    string
+		lookup chan Lookup
+	}
+	// A fila limita quantas consultas ficam em andamento à frente da que
+	// está sendo escrita
+	queue := make(chan pending, max(f.concurrency, 1))
+	var readErr error
+	go func() {
+		defer close(queue)
+		sc := bufio.NewScanner(r)
+		for sc.Scan() {
+			cep := strings.TrimSpace(sc.Text())
+			if cep == "" || strings.HasPrefix(cep, "#") {
+				continue
+			}
+			p := pending{cep: cep, lookup: make(chan Lookup, 1)}
+			queue <- p
+			go func() { p.lookup <- app.resolver.Resolve(context.Background(), cep) }()
+		}
+		readErr = sc.Err()
+	}()
+
+	code := exitOK
+	for p := range queue {
+		lookup := <-p.lookup
+		var errs bytes.Buffer
+		printLookup(os.Stdout, &errs, app.resolver, p.cep, lookup, write)
+		for _, line := range strings.Split(strings.TrimSpace(errs.String()), "\n") {
+			if line != "" {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", p.cep, line)
+			}
+		}
+		if c := lookupExitCode(lookup); code == exitOK {
+			code = c
+		}
+	}
+	if f.flush != nil {
+		f.flush()
+	}
+	if readErr != nil {
+		fmt.Fprintln(os.Stderr, readErr)
+		return exitUsage
+	}
+	return code
+}
+
+// batchSummary conta os resultados de um lote por desfecho.
+type batchSummary struct {
+	total, found, cached, notFound, invalid, timedOut, failed int
+}
+
+func summarize(lookups []Lookup) batchSummary {
+	s := batchSummary{total: len(lookups)}
+	for _, l := range lookups {
+		switch code := lookupExitCode(l); {
+		case code == exitOK:
+			s.found++
+			if l.Results[0].CacheHit {
+				s.cached++
+			}
+		case code == exitNotFound:
+			s.notFound++
+		case code == exitInvalidCEP:
+			s.invalid++
+		case code == exitTimeout:
+			s.timedOut++
+		default:
+			s.failed++
+		}
+	}
+	return s
+}
+
+// printSummary agrega os resultados de um lote por desfecho.
+func printSummary(w io.Writer, lookups []Lookup) {
+	s := summarize(lookups)
+	fmt.Fprint(w, tr("\n%d CEP(s): %d encontrados (%d do cache), %d não encontrados, %d inválidos, %d com timeout, %d com erro\n",
+		s.total, s.found, s.cached, s.notFound, s.invalid, s.timedOut, s.failed))
+}
+
+// printLookup escreve em w os resultados de lookup e em errw a mensagem de
+// erro de cada falha. Os avisos vão para a saída de erro.
+func printLookup(w, errw io.Writer, resolver *Resolver, cep string, lookup Lookup, write formatter) {
+	results := lookup.Results
+	var invalid *invalidCEPError
+	for _, err := range lookup.Warnings {
+		fmt.Fprint(os.Stderr, tr("Aviso: geocodificação falhou: %v\n", err))
+	}
+	for _, res := range results {
+		if res.Err != nil {
+			switch {
+			case len(results) > 1:
+				fmt.Fprintln(errw, paint(errw, colorError, tr("Erro na %s: %v", res.Source, res.Err)))
+			case lookup.TimedOut:
+				// Se nenhuma resposta for recebida dentro do timeout
+				fmt.Fprintln(errw, paint(errw, colorError, tr("Timeout de %s excedido", resolver.Timeout)))
+			case errors.Is(res.Err, ErrNotFound):
+				fmt.Fprintln(errw, paint(errw, colorError, tr("CEP %s não encontrado", cep)))
+			case errors.As(res.Err, &invalid):
+				fmt.Fprintln(errw, paint(errw, colorError, tr("CEP %s inválido: %s", cep, invalid.reason)))
+			case errors.Is(res.Err, ErrInvalidCEP):
+				fmt.Fprintln(errw, paint(errw, colorError, tr("CEP %s inválido", cep)))
+			default:
+				fmt.Fprintln(errw, paint(errw, colorError, tr("Erro ao buscar CEP: %v", res.Err)))
+			}
+			continue
+		}
+		if err := write(w, res); err != nil {
+			fmt.Fprint(os.Stderr, tr("Erro ao escrever a saída: %v\n", err))
+		}
+	}
+}
@@ -1,149 +1,83 @@
-package main
-
-import (
-	"context"
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"os"
-	"time"
-)
-
-type Address struct {
-	CEP          string `json:"cep"`
-	Street       string `json:"street,omitempty"`
-	Complement   string `json:"complement,omitempty"`
-	Neighborhood string `json:"neighborhood,omitempty"`
-	City         string `json:"city,omitempty"`
-	State        string `json:"state,omitempty"`
-}
-
-type BrasilAPIResponse struct {
-	CEP          string `json:"cep"`
-	State        string `json:"state"`
-	City         string `json:"city"`
-	Neighborhood string `json:"neighborhood"`
-	Street       string `json:"street"`
-}
-
-type ViaCEPResponse struct {
-	CEP         string `json:"cep"`
-	Logradouro  string `json:"logradouro"`
-	Complemento string `json:"complemento"`
-	Bairro      string `json:"bairro"`
-	Localidade  string `json:"localidade"`
-	UF          string `json:"uf"`
-}
-
-type APIResult struct {
-	Addr   Address
-	Source string
-	Err    error
-}
-
-func fetchBrasilAPI(ctx context.Context, cep string, ch chan<- APIResult) {
-	url := fmt.Sprintf("https://brasilapi.com.br/api/cep/v1/%s", cep)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		ch <- APIResult{Err: err, Source: "BrasilAPI"}
-		return
-	}
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		ch <- APIResult{Err: err, Source: "BrasilAPI"}
-		return
-	}
-	defer resp.Body.Close()
-
-	var r BrasilAPIResponse
-	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
-		ch <- APIResult{Err: err, Source: "BrasilAPI"}
-		return
-	}
-
-	ch <- APIResult{
-		Addr: Address{
-			CEP:          r.CEP,
-			Street:       r.Street,
-			Neighborhood: r.Neighborhood,
-			City:         r.City,
-			State:        r.State,
-		},
-		Source: "BrasilAPI",
-	}
-}
-
-func fetchViaCEP(ctx context.Context, cep string, ch chan<- APIResult) {
-	url := fmt.Sprintf("http://viacep.com.br/ws/%s/json/", cep)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		ch <- APIResult{Err: err, Source: "ViaCEP"}
-		return
-	}
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		ch <- APIResult{Err: err, Source: "ViaCEP"}
-		return
-	}
-	defer resp.Body.Close()
-
-	var v ViaCEPResponse
-	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
-		ch <- APIResult{Err: err, Source: "ViaCEP"}
-		return
-	}
-
-	ch <- APIResult{
-		Addr: Address{
-			CEP:          v.CEP,
-			Street:       v.Logradouro,
-			Complement:   v.Complemento,
-			Neighborhood: v.Bairro,
-			City:         v.Localidade,
-			State:        v.UF,
-		},
-		Source: "ViaCEP",
-	}
-}
-
-func main() {
-
-	if len(os.Args) != 2 {
-		fmt.Println("Uso: go run main.go <cep>")
-		os.Exit(1)
-	}
-	cep := os.Args[1]
-
-	// timeout de 1 segundo
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
-
-	ch := make(chan APIResult, 2)
-	go fetchBrasilAPI(ctx, cep, ch)
-	go fetchViaCEP(ctx, cep, ch)
-
-	select {
-	case res := <-ch:
-		// Cancela a requisição mais lenta
-		cancel()
-		if res.Err != nil {
-			fmt.Printf("Erro ao buscar CEP: %v\n", res.Err)
-			os.Exit(1)
-		}
-		fmt.Printf("Resposta da %s:\n", res.Source)
-		fmt.Printf("CEP: %s\nRua: %s\nBairro: %s\nCidade: %s\nEstado: %s\n",
-			res.Addr.CEP,
-			res.Addr.Street,
-			res.Addr.Neighborhood,
-			res.Addr.City,
-			res.Addr.State,
-		)
-	case <-ctx.Done():
-		// Se nenhuma resposta for recebida dentro do timeout
-		fmt.Println("Timeout de 1 segundo excedido")
-		os.Exit(1)
-	}
-}
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// command é um subcomando da CLI.
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(args []string) int
+	// flags declara em fs as opções do subcomando, e args lista os valores
+	// aceitos como argumento; os dois servem ao completion.
+	flags func(fs *flag.FlagSet)
+	args  []string
+}
+
+// commands lista os subcomandos, na ordem em que aparecem na ajuda. Sem
+// subcomando, os argumentos vão para lookup.
+var commands []command
+
+func init() {
+	lookupFlags := func(fs *flag.FlagSet) { registerLookupFlags(fs) }
+	optionFlags := func(fs *flag.FlagSet) { registerOptions(fs) }
+	commands = []command{
+		{name: "lookup", usage: "lookup [opções] <cep>...", summary: "consulta um ou mais CEPs", run: runLookup, flags: lookupFlags},
+		{name: "batch", usage: "batch [opções] <arquivo>", summary: "consulta os CEPs de um arquivo, um por linha", run: runBatch, flags: lookupFlags},
+		{name: "bench", usage: "bench [opções] [-n 20] <cep>", summary: "mede a latência e as vitórias de cada provider", run: runBench,
+			flags: func(fs *flag.FlagSet) { registerBenchFlags(fs) }},
+		{name: "distance", usage: "distance [opções] <cep1> <cep2>", summary: "distância em linha reta entre dois CEPs", run: runDistance,
+			flags: func(fs *flag.FlagSet) { registerDistanceFlags(fs) }},
+		{name: "near", usage: "near [opções] --lat <latitude> --lng <longitude>", summary: "o CEP mais próximo de um ponto (busca reversa)", run: runNear,
+			flags: func(fs *flag.FlagSet) { registerNearFlags(fs) }},
+		{name: "validate", usage: "validate [opções] --cep <cep> [--street <rua>] [--city ...]", summary: "confere se um endereço informado bate com o do CEP", run: runValidate,
+			flags: func(fs *flag.FlagSet) { registerValidateFlags(fs) }},
+		{name: "search", usage: "search [opções] <endereço> | --uf --city --street", summary: "procura os CEPs de um logradouro", run: runSearch,
+			flags: func(fs *flag.FlagSet) { registerSearchFlags(fs) }},
+		{name: "find", usage: "find [opções] [--uf <UF>] [--city <cidade>]", summary: "busca interativa de CEPs pelo nome da rua", run: runFind,
+			flags: func(fs *flag.FlagSet) { registerFindFlags(fs) }},
+		{name: "serve", usage: "serve [opções] [--addr localhost:8080]", summary: "API HTTP: GET /cep/{cep} devolve o endereço em JSON", run: runServe,
+			flags: func(fs *flag.FlagSet) { registerServeFlags(fs) }},
+		{name: "repl", usage: "repl [opções]", summary: "modo interativo: consulta os CEPs digitados", run: runRepl,
+			flags: func(fs *flag.FlagSet) { registerReplFlags(fs) }},
+		{name: "tui", usage: "tui [opções]", summary: "interface de tela cheia para muitas consultas seguidas", run: runTUI, flags: optionFlags},
+		{name: "cache", usage: "cache stats|clear|warm [--concurrency 4] [--rate 5] <arquivo>", summary: "inspeciona, limpa ou aquece o cache", run: runCache,
+			flags: optionFlags, args: []string{"stats", "clear", "warm"}},
+		{name: "completion", usage: "completion bash|zsh|fish", summary: "gera o script de completion do shell", run: runCompletion,
+			args: completionShells},
+		{name: "version", usage: "version", summary: "mostra a versão", run: runVersion},
+		{name: "help", usage: "help", summary: "mostra esta ajuda", run: func([]string) int { printUsage(os.Stdout); return exitOK }},
+		{name: "__complete", run: runComplete},
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	if len(args) > 0 {
+		for _, cmd := range commands {
+			if args[0] == cmd.name {
+				return cmd.run(args[1:])
+			}
+		}
+	}
+	return runLookup(args)
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, tr("Uso: go run main.go [lookup] [--providers BrasilAPI,ViaCEP,...] [--country BR] [--strategy race] [--timeout 1s] [--format text] <cep>..."))
+	fmt.Fprintln(w, tr("\nSubcomandos:"))
+	for _, cmd := range commands {
+		if cmd.usage != "" {
+			fmt.Fprintf(w, "  %-62s %s\n", tr(cmd.usage), tr(cmd.summary))
+		}
+	}
+	fmt.Fprintln(w, tr("\nUse go run main.go <subcomando> -h para ver as opções."))
+}
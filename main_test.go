@@ -0,0 +1,13 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+// As mensagens esperadas pelos testes estão em português, qualquer que seja
+// o idioma do ambiente.
+func TestMain(m *testing.M) {
+	lang = "pt"
+	os.Exit(m.Run())
+}
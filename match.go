@@ -0,0 +1,108 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// matchKey prepara s para comparação: sem acentos, em minúsculas e só com
+// letras e dígitos separados por um espaço.
+func matchKey(s string) string {
+	s = strings.ToLower(stripAccents(s))
+	return strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// similarity é a semelhança entre a e b, de 0 a 1, pela distância de edição
+// entre as formas de matchKey.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(matchKey(a)), []rune(matchKey(b))
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(max(len(ra), len(rb)))
+}
+
+// levenshtein é o número mínimo de inserções, remoções e trocas de letras
+// que transformam a em b.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := range a {
+		cur[0] = i + 1
+		for j := range b {
+			cost := 1
+			if a[i] == b[j] {
+				cost = 0
+			}
+			cur[j+1] = min(prev[j+1]+1, cur[j]+1, prev[j]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// streetSimilarity compara logradouros pelo nome, desconsiderando o tipo
+// quando só um dos lados o informa ("Paulista" e "Av. Paulista"). Tipos
+// diferentes ("Rua" e "Avenida") pesam contra.
+func streetSimilarity(want, got string) float64 {
+	wt, wrest, wok := findStreetType(want)
+	gt, grest, gok := findStreetType(got)
+	if !wok {
+		wrest = want
+	}
+	if !gok {
+		grest = got
+	}
+	score := similarity(wrest, grest)
+	if wok && gok && wt.name != gt.name {
+		score *= 0.8
+	}
+	return score
+}
+
+// stateSimilarity aceita tanto a UF quanto o nome do estado em want.
+func stateSimilarity(want, got string) float64 {
+	return max(similarity(want, got), similarity(want, stateNames[strings.ToUpper(got)]))
+}
+
+// fieldMatch é a comparação de um campo informado com o do endereço.
+type fieldMatch struct {
+	Field string  `json:"field"`
+	Input string  `json:"input"`
+	Found string  `json:"found"`
+	Score float64 `json:"score"`
+}
+
+// matchFields compara os campos preenchidos de want com os de got (rua,
+// bairro, cidade e UF) e devolve a comparação de cada um e a média.
+func matchFields(want, got Address) ([]fieldMatch, float64) {
+	compare := []struct {
+		name      string
+		want, got string
+		sim       func(want, got string) float64
+	}{
+		{"street", want.Street, got.Street, streetSimilarity},
+		{"neighborhood", want.Neighborhood, got.Neighborhood, similarity},
+		{"city", want.City, got.City, similarity},
+		{"state", want.State, got.State, stateSimilarity},
+	}
+	var matches []fieldMatch
+	total := 0.0
+	for _, c := range compare {
+		if strings.TrimSpace(c.want) == "" {
+			continue
+		}
+		score := c.sim(c.want, c.got)
+		matches = append(matches, fieldMatch{Field: c.name, Input: c.want, Found: c.got, Score: score})
+		total += score
+	}
+	if len(matches) == 0 {
+		return nil, 0
+	}
+	return matches, total / float64(len(matches))
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// counterVec é um contador com um rótulo (em geral, o nome do provider).
+type counterVec struct {
+	mu     sync.Mutex
+	values map[string]int64
+}
+
+func (c *counterVec) inc(label string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.values == nil {
+		c.values = map[string]int64{}
+	}
+	c.values[label]++
+}
+
+// snapshot devolve uma cópia dos valores atuais.
+func (c *counterVec) snapshot() map[string]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make(map[string]int64, len(c.values))
+	for k, v := range c.values {
+		out[k] = v
+	}
+	return out
+}
+
+// rateLimitedTotal conta as respostas 429 recebidas de cada provider.
+var rateLimitedTotal = &counterVec{}
+
+// raceWinsTotal conta, por provider, as consultas resolvidas pela resposta
+// dele.
+var raceWinsTotal = &counterVec{}
+
+// Métricas do subcomando serve.
+var (
+	httpRequestsTotal      = &counterVec{}
+	providerRequestsTotal  = &counterVec{}
+	providerErrorsTotal    = &counterVec{}
+	providerLatencySeconds = &histogramVec{buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}}
+)
+
+// histogramVec é um histograma com um rótulo, com os limites buckets (em
+// ordem crescente), como os do Prometheus.
+type histogramVec struct {
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*histogram
+}
+
+type histogram struct {
+	counts []int64 // counts[i] conta as observações <= buckets[i]
+	count  int64
+	sum    float64
+}
+
+func (h *histogramVec) observe(label string, d time.Duration) {
+	v := d.Seconds()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.series == nil {
+		h.series = map[string]*histogram{}
+	}
+	s := h.series[label]
+	if s == nil {
+		s = &histogram{counts: make([]int64, len(h.buckets))}
+		h.series[label] = s
+	}
+	for i, b := range h.buckets {
+		if v <= b {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.sum += v
+}
+
+// labelEscaper escapa valores de rótulo no formato de texto do Prometheus.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// writeCounter escreve c no formato de texto do Prometheus, com o rótulo
+// label.
+func writeCounter(w io.Writer, name, help, label string, c *counterVec) {
+	values := c.snapshot()
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	for _, k := range slices.Sorted(maps.Keys(values)) {
+		fmt.Fprintf(w, "%s{%s=\"%s\"} %d\n", name, label, labelEscaper.Replace(k), values[k])
+	}
+}
+
+// writeGauge escreve um valor sem rótulos.
+func writeGauge(w io.Writer, name, help string, v float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %s\n", name, help, name, name, formatFloat(v))
+}
+
+// write escreve h no formato de texto do Prometheus, com o rótulo label.
+func (h *histogramVec) write(w io.Writer, name, help, label string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	for _, k := range slices.Sorted(maps.Keys(h.series)) {
+		s := h.series[k]
+		l := fmt.Sprintf("%s=\"%s\"", label, labelEscaper.Replace(k))
+		for i, b := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket{%s,le=\"%s\"} %d\n", name, l, formatFloat(b), s.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, l, s.count)
+		fmt.Fprintf(w, "%s_sum{%s} %s\n", name, l, formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, l, s.count)
+	}
+}
+
+// writeMetrics escreve todas as métricas do processo no formato de texto do
+// Prometheus.
+func writeMetrics(w io.Writer) {
+	writeCounter(w, "cep_http_requests_total", "Requisições HTTP atendidas, por status.", "code", httpRequestsTotal)
+	writeCounter(w, "cep_provider_requests_total", "Consultas feitas a cada provider.", "provider", providerRequestsTotal)
+	writeCounter(w, "cep_provider_errors_total", "Consultas a cada provider que falharam (sem contar CEP não encontrado).", "provider", providerErrorsTotal)
+	providerLatencySeconds.write(w, "cep_provider_latency_seconds", "Latência das respostas de cada provider.", "provider")
+	writeCounter(w, "cep_race_wins_total", "Consultas resolvidas pela resposta de cada provider.", "provider", raceWinsTotal)
+	writeCounter(w, "cep_rate_limited_total", "Respostas 429 recebidas de cada provider.", "provider", rateLimitedTotal)
+	writeCounter(w, "cep_cache_lookups_total", "Consultas ao cache, por resultado (hit, miss, stale).", "result", cacheLookupsTotal)
+
+	writeGauge(w, "cep_cache_hit_ratio", "Fração das consultas ao cache respondidas por ele.", cacheHitRatio(cacheLookupsTotal.snapshot()))
+}
+
+// cacheHitRatio é hit/(hit+miss), como no cache stats. Um endereço
+// desatualizado servido (stale) já foi contado como miss.
+func cacheHitRatio(lookups map[string]int64) float64 {
+	total := lookups["hit"] + lookups["miss"]
+	if total == 0 {
+		return 0
+	}
+	return float64(lookups["hit"]) / float64(total)
+}
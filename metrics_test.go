@@ -0,0 +1,22 @@
+package main
+
+import "testing"
+
+func TestCacheHitRatio(t *testing.T) {
+	tests := []struct {
+		name    string
+		lookups map[string]int64
+		want    float64
+	}{
+		{"sem consultas", map[string]int64{}, 0},
+		{"só acertos", map[string]int64{"hit": 4}, 1},
+		{"metade", map[string]int64{"hit": 2, "miss": 2}, 0.5},
+		// O stale já entrou como miss e não pode contar duas vezes
+		{"com stale", map[string]int64{"hit": 3, "miss": 1, "stale": 1}, 0.75},
+	}
+	for _, tt := range tests {
+		if got := cacheHitRatio(tt.lookups); got != tt.want {
+			t.Errorf("%s: cacheHitRatio(%v) = %v, quero %v", tt.name, tt.lookups, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// nearFlags são as opções do subcomando near.
+type nearFlags struct {
+	lookupFlags
+	lat, lng float64
+}
+
+func registerNearFlags(fs *flag.FlagSet) *nearFlags {
+	f := &nearFlags{lookupFlags: lookupFlags{opts: registerOptions(fs)}}
+	f.registerOutputFlags(fs)
+	fs.Float64Var(&f.lat, "lat", 0, "latitude do ponto, ex.: -23.56")
+	fs.Float64Var(&f.lng, "lng", 0, "longitude do ponto, ex.: -46.65")
+	return f
+}
+
+// runNear encontra, com a busca reversa do geocoder, o endereço mais
+// próximo de um ponto e mostra o seu CEP, completado pelos providers.
+func runNear(args []string) int {
+	fs := flag.NewFlagSet("cep near", flag.ExitOnError)
+	f := registerNearFlags(fs)
+	parseFlags(fs, args)
+
+	if fs.NArg() > 0 || !flagSet(fs, "lat") || !flagSet(fs, "lng") {
+		fmt.Println(tr("Uso: go run main.go near [opções] --lat -23.56 --lng -46.65"))
+		return exitUsage
+	}
+	if f.lat < -90 || f.lat > 90 || f.lng < -180 || f.lng > 180 {
+		fmt.Println(tr("coordenadas fora do intervalo: a latitude vai de -90 a 90 e a longitude de -180 a 180"))
+		return exitUsage
+	}
+	write, err := f.formatter()
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+
+	geocoders, err := selectGeocoders(f.opts.geocoder)
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	var reversers []ReverseGeocoder
+	for _, g := range geocoders {
+		if r, ok := g.(ReverseGeocoder); ok {
+			reversers = append(reversers, r)
+		}
+	}
+	if len(reversers) == 0 {
+		fmt.Println(tr("nenhum dos geocoders faz busca reversa"))
+		return exitUsage
+	}
+
+	app, err := f.opts.setup()
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	defer app.Close()
+
+	errw := io.Writer(os.Stdout)
+	if f.quiet {
+		errw = os.Stderr
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), max(app.resolver.Timeout, geocodeTimeout))
+	defer cancel()
+	point := Location{Latitude: f.lat, Longitude: f.lng}
+	var found *APIResult
+	for _, r := range reversers {
+		addr, err := r.Reverse(ctx, point)
+		if err != nil {
+			fmt.Fprintln(errw, paint(errw, colorError, tr("Erro na busca reversa (%s): %v", r.Name(), err)))
+			continue
+		}
+		found = &APIResult{Addr: addr, Source: r.Name()}
+		break
+	}
+	if found == nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return exitTimeout
+		}
+		return exitProvidersError
+	}
+
+	cep := normalizeCEP(found.Addr.CEP)
+	if validateCEP(cep) != nil {
+		fmt.Fprintln(errw, paint(errw, colorError, tr("Nenhum CEP encontrado perto de %g, %g", f.lat, f.lng)))
+		return exitNotFound
+	}
+	// O endereço dos providers é o oficial do CEP; o do geocoder fica só se
+	// a consulta falhar
+	lookup := app.resolver.Resolve(ctx, cep)
+	if lookup.OK() {
+		for _, res := range lookup.Results {
+			if res.Err == nil {
+				if res.Addr.Location == nil {
+					res.Addr.Location = found.Addr.Location
+				}
+				found = &res
+				break
+			}
+		}
+	} else {
+		found.Addr.CEP = cep
+		*found = app.resolver.transform(Lookup{Results: []APIResult{*found}}).Results[0]
+	}
+
+	if err := write(os.Stdout, *found); err != nil {
+		fmt.Fprint(os.Stderr, tr("Erro ao escrever a saída: %v\n", err))
+	}
+	if f.flush != nil {
+		f.flush()
+	}
+	return exitOK
+}
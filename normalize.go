@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// textTransforms são as transformações de --normalize, aplicadas aos campos
+// de texto livre do endereço (a UF e os códigos ficam como estão).
+var textTransforms = map[string]func(string) string{
+	"ascii": stripAccents,
+	"upper": strings.ToUpper,
+	"title": titleCase,
+}
+
+// normalizeTransform devolve a transformação de --normalize, uma lista de
+// nomes de textTransforms separados por vírgula aplicados em ordem.
+func normalizeTransform(list string) (func(Address) Address, error) {
+	var fs []func(string) string
+	for _, name := range strings.Split(list, ",") {
+		f, ok := textTransforms[strings.TrimSpace(name)]
+		if !ok {
+			return nil, errors.New(tr("--normalize desconhecido: %s (use ascii, upper ou title)", name))
+		}
+		fs = append(fs, f)
+	}
+	return func(a Address) Address {
+		for _, p := range []*string{&a.Street, &a.Complement, &a.Neighborhood, &a.City} {
+			for _, f := range fs {
+				*p = f(*p)
+			}
+		}
+		return a
+	}, nil
+}
+
+// stripAccents troca as letras acentuadas pelas sem acento ("São Paulo" vira
+// "Sao Paulo"), para sistemas que só aceitam ASCII.
+func stripAccents(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	out, _, err := transform.String(t, s)
+	if err != nil {
+		return s
+	}
+	return out
+}
+
+// titleParticles ficam em minúsculas no meio de um nome próprio.
+var titleParticles = map[string]bool{"a": true, "e": true, "o": true, "da": true, "das": true, "de": true, "do": true, "dos": true, "em": true}
+
+var romanNumeral = regexp.MustCompile(`^m{0,3}(cm|cd|d?c{0,3})(xc|xl|l?x{0,3})(ix|iv|v?i{0,3})$`)
+
+// titleCase escreve s com a primeira letra de cada palavra maiúscula, como
+// em "Praça da Sé", mantendo em maiúsculas os numerais romanos ("Rua XV de
+// Novembro").
+func titleCase(s string) string {
+	words := strings.Fields(strings.ToLower(s))
+	for i, w := range words {
+		switch {
+		case i > 0 && titleParticles[w]:
+		case len(w) > 1 && romanNumeral.MatchString(w):
+			words[i] = strings.ToUpper(w)
+		default:
+			r := []rune(w)
+			r[0] = unicode.ToUpper(r[0])
+			words[i] = string(r)
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// openAPISchemaNames são os nomes, em components.schemas, dos corpos das
+// respostas. Os outros tipos entram no esquema de quem os usa.
+var openAPISchemaNames = map[reflect.Type]string{
+	reflect.TypeFor[jsonResult]():     "Address",
+	reflect.TypeFor[errorResponse]():  "Error",
+	reflect.TypeFor[healthResponse](): "Health",
+	reflect.TypeFor[readyResponse]():  "Ready",
+}
+
+var pathParam = regexp.MustCompile(`\{(\w+)\}`)
+
+func (s *server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	writeJSONResponse(w, http.StatusOK, openAPIDocument(s.routes()))
+}
+
+// openAPIDocument descreve routes em OpenAPI 3. Os esquemas saem, por
+// reflexão, das tags json dos tipos das respostas.
+func openAPIDocument(routes []route) map[string]any {
+	schemas := map[string]any{}
+	paths := map[string]map[string]any{}
+	secured := false
+	for _, rt := range routes {
+		responses := map[string]any{}
+		for _, resp := range rt.responses {
+			r := map[string]any{"description": resp.description}
+			if resp.body != nil {
+				r["content"] = map[string]any{resp.contentType: map[string]any{"schema": schemaRef(reflect.TypeOf(resp.body), schemas)}}
+			} else if resp.contentType != "" {
+				r["content"] = map[string]any{resp.contentType: map[string]any{"schema": map[string]any{"type": "object"}}}
+			}
+			responses[strconv.Itoa(resp.status)] = r
+		}
+		op := map[string]any{"summary": rt.summary, "responses": responses}
+		if rt.auth {
+			op["security"] = []any{map[string]any{"apiKey": []any{}}, map[string]any{"bearer": []any{}}}
+			secured = true
+		}
+		var params []any
+		for _, m := range pathParam.FindAllStringSubmatch(rt.path, -1) {
+			params = append(params, map[string]any{"name": m[1], "in": "path", "required": true, "schema": map[string]any{"type": "string"}})
+		}
+		if params != nil {
+			op["parameters"] = params
+		}
+		if paths[rt.path] == nil {
+			paths[rt.path] = map[string]any{}
+		}
+		paths[rt.path][strings.ToLower(rt.method)] = op
+	}
+	components := map[string]any{"schemas": schemas}
+	if secured {
+		components["securitySchemes"] = map[string]any{
+			"apiKey": map[string]any{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+			"bearer": map[string]any{"type": "http", "scheme": "bearer"},
+		}
+	}
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "cep",
+			"version":     version,
+			"description": "Consulta de CEPs com corrida entre vários providers.",
+		},
+		"paths":      paths,
+		"components": components,
+	}
+}
+
+// schemaRef devolve o esquema de t, ou uma referência a ele se t tiver nome
+// em openAPISchemaNames; nesse caso o esquema vai para schemas.
+func schemaRef(t reflect.Type, schemas map[string]any) map[string]any {
+	if name, ok := openAPISchemaNames[t]; ok {
+		if _, done := schemas[name]; !done {
+			schemas[name] = map[string]any{} // evita recursão infinita
+			schemas[name] = schemaOf(t, schemas)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	}
+	return schemaOf(t, schemas)
+}
+
+func schemaOf(t reflect.Type, schemas map[string]any) map[string]any {
+	if t == reflect.TypeFor[time.Time]() {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := schemaRef(t.Elem(), schemas)
+		if _, ref := s["$ref"]; !ref {
+			s["nullable"] = true
+		}
+		return s
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaRef(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaRef(t.Elem(), schemas)}
+	case reflect.Struct:
+		props := map[string]any{}
+		var required []string
+		addStructFields(t, props, &required, schemas)
+		s := map[string]any{"type": "object", "properties": props}
+		if required != nil {
+			s["required"] = required
+		}
+		return s
+	}
+	return map[string]any{}
+}
+
+// addStructFields acrescenta a props os campos de t com tag json, incluindo
+// os dos structs embutidos, como encoding/json os escreve. Os campos sem
+// omitempty vão para required.
+func addStructFields(t reflect.Type, props map[string]any, required *[]string, schemas map[string]any) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if f.Anonymous && tag == "" {
+			addStructFields(f.Type, props, required, schemas)
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if !f.IsExported() || name == "-" || name == "" {
+			continue
+		}
+		props[name] = schemaRef(f.Type, schemas)
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
@@ -0,0 +1,458 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// formatter escreve um resultado bem-sucedido em w.
+type formatter func(w io.Writer, res APIResult) error
+
+// formats são os formatos aceitos por --format.
+var formats = map[string]formatter{
+	"text": writeText,
+	"json": writeJSON,
+	"tsv":  writeTSV,
+	"yaml": writeYAML,
+	"csv":  writeCSV,
+	"xml":  writeXML,
+
+	"oneline": writeOneLine,
+	"label":   writeLabel,
+	"ndjson":  writeNDJSON,
+	"vcard":   writeVCard,
+}
+
+// formatHeaders escrevem o cabeçalho dos formatos que têm um, antes do
+// primeiro resultado.
+var formatHeaders = map[string]func(w io.Writer) error{
+	"csv": writeCSVHeader,
+}
+
+// lineFormats são os formatos escritos sem linha em branco entre os
+// resultados: os de uma linha por resultado e o vcard, cujos cartões são
+// simplesmente concatenados.
+var lineFormats = map[string]bool{"tsv": true, "csv": true, "oneline": true, "ndjson": true, "vcard": true}
+
+func formatNames() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	// table e geojson não estão em formats por precisarem de todos os
+	// resultados; veja tableFormatter e geojsonFormatter
+	names = append(names, "table", "geojson")
+	sort.Strings(names)
+	return names
+}
+
+// writeText escreve o endereço no formato de texto padrão.
+func writeText(w io.Writer, res APIResult) error {
+	source := paint(w, colorWinner, res.Source)
+	switch {
+	case res.Stale:
+		fmt.Fprint(w, tr("Resposta da %s (cache desatualizado):\n", source))
+	case res.CacheHit:
+		fmt.Fprint(w, tr("Resposta da %s (cache):\n", source))
+	default:
+		fmt.Fprint(w, tr("Resposta da %s:\n", source))
+	}
+	return writeTextBody(w, res)
+}
+
+// writeTextBody escreve os campos do endereço, sem o cabeçalho.
+func writeTextBody(w io.Writer, res APIResult) error {
+	field := func(label, format string, value any) {
+		fmt.Fprintf(w, "%s "+format+"\n", paint(w, colorLabel, tr(label)+":"), value)
+	}
+	field("CEP", "%s", res.Addr.CEP)
+	field("Rua", "%s", res.Addr.Street)
+	field("Bairro", "%s", res.Addr.Neighborhood)
+	field("Cidade", "%s", res.Addr.City)
+	state := res.Addr.State
+	if name := stateNameFor(res.Addr); name != "" {
+		state += " (" + name + ")"
+	}
+	field("Estado", "%s", state)
+	if res.Addr.Complement != "" {
+		field("Complemento", "%s", res.Addr.Complement)
+	}
+	if res.Addr.Country != "" {
+		field("País", "%s", res.Addr.Country)
+	}
+	if res.Addr.DDD != "" {
+		field("DDD", "%s", res.Addr.DDD)
+	}
+	if res.Addr.IBGE != "" {
+		field("Código IBGE", "%s", res.Addr.IBGE)
+	}
+	if res.Addr.SIAFI != "" {
+		field("Código SIAFI", "%s", res.Addr.SIAFI)
+	}
+	if res.Addr.GIA != "" {
+		field("Código GIA", "%s", res.Addr.GIA)
+	}
+	if region := regionFor(res.Addr); region != "" {
+		field("Região", "%s", region)
+	}
+	if tz := timezoneFor(res.Addr); tz != "" {
+		field("Fuso horário", "%s", tz)
+	}
+	if loc := res.Addr.Location; loc != nil {
+		field("Latitude", "%f", loc.Latitude)
+		field("Longitude", "%f", loc.Longitude)
+		if loc.Altitude != 0 {
+			field("Altitude", "%.1f", loc.Altitude)
+		}
+		if loc.DisplayName != "" {
+			field("Local", "%s", loc.DisplayName)
+		}
+	}
+	if mapLinks != "" {
+		field("Mapa", "%s", mapURL(mapLinks, res.Addr))
+	}
+	if res.Confidence > 0 {
+		field("Confiança", "%.0f%%", res.Confidence*100)
+	}
+	writeProvenance(w, res)
+	return nil
+}
+
+// mapLinks é o serviço de mapas dos links da saída de texto (--map): google,
+// osm ou vazio, sem link.
+var mapLinks string
+
+func setMapLinks(s string) error {
+	switch s {
+	case "google", "osm":
+		mapLinks = s
+		return nil
+	}
+	return errors.New(tr("mapa desconhecido: %s (use google ou osm)", s))
+}
+
+// mapURL devolve o link do endereço a no Google Maps ou no OpenStreetMap,
+// conforme service: pelas coordenadas, se houver, ou por uma busca do
+// endereço.
+func mapURL(service string, a Address) string {
+	if loc := a.Location; loc != nil {
+		lat, lng := strconv.FormatFloat(loc.Latitude, 'f', 6, 64), strconv.FormatFloat(loc.Longitude, 'f', 6, 64)
+		if service == "osm" {
+			return "https://www.openstreetmap.org/?mlat=" + lat + "&mlon=" + lng + "#map=17/" + lat + "/" + lng
+		}
+		return "https://www.google.com/maps/search/?api=1&query=" + lat + "," + lng
+	}
+	country := a.Country
+	if country == "" {
+		country = "Brasil"
+	}
+	var parts []string
+	for _, s := range []string{a.Street, a.Neighborhood, a.City, a.State, formatCEP(a.CEP), country} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	query := url.QueryEscape(strings.Join(parts, ", "))
+	if service == "osm" {
+		return "https://www.openstreetmap.org/search?query=" + query
+	}
+	return "https://www.google.com/maps/search/?api=1&query=" + query
+}
+
+// writeProvenance lista a origem de cada campo de um resultado do modo merge.
+func writeProvenance(w io.Writer, res APIResult) {
+	if len(res.Provenance) == 0 {
+		return
+	}
+	fmt.Fprintln(w, paint(w, colorLabel, tr("Origem dos campos:")))
+	for _, name := range append(addressFieldNames, "location") {
+		if src, ok := res.Provenance[name]; ok {
+			fmt.Fprintf(w, "  %s %s\n", paint(w, colorLabel, name+":"), src)
+		}
+	}
+}
+
+// writeTSV escreve o resultado numa única linha, com os campos de
+// addressFieldNames e o provider separados por tabulação.
+func writeTSV(w io.Writer, res APIResult) error {
+	values := make([]string, 0, len(addressFieldNames)+1)
+	for _, name := range addressFieldNames {
+		values = append(values, strings.ReplaceAll(*addressFields[name](&res.Addr), "\t", " "))
+	}
+	values = append(values, res.Source)
+	_, err := fmt.Fprintln(w, strings.Join(values, "\t"))
+	return err
+}
+
+// writeOneLine escreve o endereço numa linha, como em formulários de envio:
+// "Avenida Paulista, Bela Vista, São Paulo - SP, 01310-100".
+func writeOneLine(w io.Writer, res APIResult) error {
+	_, err := fmt.Fprintln(w, oneLine(res.Addr))
+	return err
+}
+
+// oneLine junta os campos preenchidos de a como em writeOneLine.
+func oneLine(a Address) string {
+	city := a.City
+	if a.State != "" {
+		city = strings.TrimPrefix(city+" - "+a.State, " - ")
+	}
+	var parts []string
+	for _, s := range []string{a.Street, a.Neighborhood, city, formatCEP(a.CEP)} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// labelRecipient é o destinatário do formato label (--recipient).
+var labelRecipient string
+
+// writeLabel escreve o endereço no leiaute de envelope dos Correios: o
+// destinatário, o logradouro, o bairro, a cidade com a UF e, por último, o
+// CEP.
+func writeLabel(w io.Writer, res APIResult) error {
+	a := res.Addr
+	street := a.Street
+	if a.Complement != "" {
+		street = strings.TrimPrefix(street+" - "+a.Complement, " - ")
+	}
+	city := strings.TrimPrefix(a.City+" - "+a.State, " - ")
+	for _, line := range []string{labelRecipient, street, a.Neighborhood, strings.TrimSuffix(city, " - "), formatCEP(a.CEP)} {
+		if line != "" {
+			fmt.Fprintln(w, line)
+		}
+	}
+	return nil
+}
+
+// writeVCard escreve o endereço como um vCard 4.0 (RFC 6350) com a
+// propriedade ADR, para importação em agendas de contatos. O nome (FN, que o
+// vCard exige) é o de --recipient ou, sem ele, o logradouro.
+func writeVCard(w io.Writer, res APIResult) error {
+	a := res.Addr
+	esc := strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\n", `\n`).Replace
+	name := labelRecipient
+	if name == "" {
+		name = strings.TrimPrefix(a.Street+", "+a.City, ", ")
+	}
+	// O vCard não tem bairro: ele vai, com o complemento, no endereço
+	// estendido
+	var extended []string
+	for _, s := range []string{a.Complement, a.Neighborhood} {
+		if s != "" {
+			extended = append(extended, esc(s))
+		}
+	}
+	lines := []string{
+		"BEGIN:VCARD",
+		"VERSION:4.0",
+		"FN:" + esc(name),
+		"ADR:;" + strings.Join(extended, `\, `) + ";" + esc(a.Street) + ";" + esc(a.City) + ";" + esc(a.State) + ";" + esc(formatCEP(a.CEP)) + ";" + esc(a.Country),
+	}
+	if loc := a.Location; loc != nil {
+		lines = append(lines, fmt.Sprintf("GEO:geo:%f,%f", loc.Latitude, loc.Longitude))
+	}
+	lines = append(lines, "END:VCARD")
+	_, err := io.WriteString(w, strings.Join(lines, "\r\n")+"\r\n")
+	return err
+}
+
+// csvFields são as colunas do formato csv com campos do endereço; seguem-se
+// source, elapsed_ms e cache_hit.
+var csvFields = []string{"cep", "street", "neighborhood", "city", "state"}
+
+func writeCSVHeader(w io.Writer) error {
+	return writeCSVRecord(w, append(csvFields, "source", "elapsed_ms", "cache_hit"))
+}
+
+// writeCSV escreve o resultado numa linha CSV com as colunas de
+// writeCSVHeader.
+func writeCSV(w io.Writer, res APIResult) error {
+	record := make([]string, 0, len(csvFields)+3)
+	for _, name := range csvFields {
+		record = append(record, *addressFields[name](&res.Addr))
+	}
+	record = append(record, res.Source, strconv.FormatFloat(elapsedMS(res), 'f', -1, 64), strconv.FormatBool(res.CacheHit))
+	return writeCSVRecord(w, record)
+}
+
+func writeCSVRecord(w io.Writer, record []string) error {
+	cw := csv.NewWriter(w)
+	cw.Write(record)
+	cw.Flush()
+	return cw.Error()
+}
+
+// tableColumns são as colunas do formato table.
+var tableColumns = []string{"CEP", "Rua", "Bairro", "Cidade", "Estado", "Fonte"}
+
+// tableRow devolve as colunas de tableColumns de res.
+func tableRow(res APIResult) []string {
+	source := res.Source
+	switch {
+	case res.Stale:
+		source = tr("%s (cache desatualizado)", source)
+	case res.CacheHit:
+		source = tr("%s (cache)", source)
+	}
+	a := res.Addr
+	return []string{a.CEP, a.Street, a.Neighborhood, a.City, a.State, source}
+}
+
+// tableFormatter devolve um formatter que acumula os resultados, com as
+// colunas dadas por row, numa tabela de colunas alinhadas, e a função que a
+// escreve, com o cabeçalho header, no w da primeira chamada. Sem resultados,
+// flush não escreve nada.
+func tableFormatter(header []string, row func(APIResult) []string) (write formatter, flush func() error) {
+	var tw *tabwriter.Writer
+	write = func(w io.Writer, res APIResult) error {
+		if tw == nil {
+			// Sem cores: os códigos ANSI contariam na largura das colunas
+			tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, strings.Join(header, "\t"))
+		}
+		_, err := fmt.Fprintln(tw, strings.Join(row(res), "\t"))
+		return err
+	}
+	flush = func() error {
+		if tw == nil {
+			return nil
+		}
+		return tw.Flush()
+	}
+	return write, flush
+}
+
+// templateData é o que um modelo de --template enxerga: os campos do Address
+// ({{.Street}}, {{.City}}, ...) e os da resposta ({{.Source}}, {{.Latency}}).
+type templateData struct {
+	Address
+	StateName string
+	Region    string
+	Timezone  string
+	Source    string
+	Cached    bool
+	Stale     bool
+	Latency   time.Duration
+}
+
+// templateFormatter devolve um formatter que escreve cada resultado com o
+// modelo text/template text, terminando a linha se o modelo não o fizer.
+func templateFormatter(text string) (formatter, error) {
+	t, err := template.New("template").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return func(w io.Writer, res APIResult) error {
+		var b strings.Builder
+		data := templateData{Address: res.Addr, StateName: stateNameFor(res.Addr), Region: regionFor(res.Addr), Timezone: timezoneFor(res.Addr), Source: res.Source, Cached: res.CacheHit, Stale: res.Stale, Latency: res.Latency}
+		if err := t.Execute(&b, data); err != nil {
+			return err
+		}
+		if !strings.HasSuffix(b.String(), "\n") {
+			b.WriteByte('\n')
+		}
+		_, err := io.WriteString(w, b.String())
+		return err
+	}, nil
+}
+
+// jsonResult é a forma de um resultado nos formatos json, ndjson, yaml e
+// xml. source, elapsed_ms e cache_hit estão sempre presentes, para que quem
+// processa os dados saiba de onde veio cada registro e quanto ele demorou.
+type jsonResult struct {
+	Address
+	// StateName, Region e Timezone são derivados da UF e do município; veja
+	// stateNameFor, regionFor e timezoneFor.
+	StateName  string            `json:"state_name,omitempty"`
+	Region     string            `json:"region,omitempty"`
+	Timezone   string            `json:"timezone,omitempty"`
+	Source     string            `json:"source"`
+	ElapsedMS  float64           `json:"elapsed_ms"`
+	CacheHit   bool              `json:"cache_hit"`
+	Stale      bool              `json:"stale,omitempty"`
+	Confidence float64           `json:"confidence,omitempty"`
+	Provenance map[string]string `json:"provenance,omitempty"`
+}
+
+func newJSONResult(res APIResult) jsonResult {
+	return jsonResult{
+		Address:    res.Addr,
+		StateName:  stateNameFor(res.Addr),
+		Region:     regionFor(res.Addr),
+		Timezone:   timezoneFor(res.Addr),
+		Source:     res.Source,
+		ElapsedMS:  elapsedMS(res),
+		CacheHit:   res.CacheHit,
+		Stale:      res.Stale,
+		Confidence: res.Confidence,
+		Provenance: res.Provenance,
+	}
+}
+
+// elapsedMS é a latência do provider em milissegundos, com precisão de
+// microssegundos.
+func elapsedMS(res APIResult) float64 {
+	return float64(res.Latency.Microseconds()) / 1000
+}
+
+func writeJSON(w io.Writer, res APIResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(newJSONResult(res))
+}
+
+// writeNDJSON escreve o resultado do formato json numa única linha.
+func writeNDJSON(w io.Writer, res APIResult) error {
+	return json.NewEncoder(w).Encode(newJSONResult(res))
+}
+
+// writeYAML escreve o resultado como um documento YAML com os mesmos campos,
+// na mesma ordem, do formato json: o JSON gerado é lido como YAML (do qual é
+// um subconjunto) e reescrito em estilo de bloco.
+func writeYAML(w io.Writer, res APIResult) error {
+	data, err := json.Marshal(newJSONResult(res))
+	if err != nil {
+		return err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	return writeYAMLNode(w, &doc)
+}
+
+// writeYAMLNode escreve doc como um documento YAML em estilo de bloco.
+func writeYAMLNode(w io.Writer, doc *yaml.Node) error {
+	blockStyle(doc)
+	fmt.Fprintln(w, "---")
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// blockStyle tira de n e dos seus filhos o estilo herdado do JSON (chaves e
+// aspas), deixando o encoder escolher o estilo padrão.
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
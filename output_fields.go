@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fieldNames são os campos aceitos por --fields: os de texto do Address, as
+// coordenadas e os derivados do endereço.
+var fieldNames = append(slices.Clone(addressFieldNames), "latitude", "longitude", "state_name", "region", "timezone")
+
+// parseFields interpreta a lista de --fields.
+func parseFields(s string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(fieldNames, name) {
+			return nil, errors.New(tr("campo desconhecido: %s (use %s)", name, strings.Join(fieldNames, ", ")))
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// field é um campo escolhido por --fields e o seu valor num resultado.
+type field struct {
+	name  string
+	value any
+}
+
+// selectFields devolve os campos names de res, na ordem pedida. As
+// coordenadas ausentes ficam vazias.
+func selectFields(res APIResult, names []string) []field {
+	fields := make([]field, len(names))
+	for i, name := range names {
+		var value any = ""
+		switch loc := res.Addr.Location; {
+		case name == "latitude" && loc != nil:
+			value = loc.Latitude
+		case name == "longitude" && loc != nil:
+			value = loc.Longitude
+		case name == "state_name":
+			value = stateNameFor(res.Addr)
+		case name == "region":
+			value = regionFor(res.Addr)
+		case name == "timezone":
+			value = timezoneFor(res.Addr)
+		case addressFields[name] != nil:
+			value = *addressFields[name](&res.Addr)
+		}
+		fields[i] = field{name, value}
+	}
+	return fields
+}
+
+// fieldsJSON escreve fields como um objeto JSON, na ordem dos campos.
+func fieldsJSON(fields []field) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(f.name)
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// fieldsNode monta fields como um mapeamento YAML, usado pelos formatos yaml
+// e xml.
+func fieldsNode(fields []field) (*yaml.Node, error) {
+	n := &yaml.Node{Kind: yaml.MappingNode}
+	for _, f := range fields {
+		var value yaml.Node
+		if err := value.Encode(f.value); err != nil {
+			return nil, err
+		}
+		n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: f.name}, &value)
+	}
+	return n, nil
+}
+
+// fieldsFormatter devolve um formatter que escreve, no formato format, só os
+// campos names de cada resultado; os metadados (source, elapsed_ms...)
+// também ficam de fora. flush é não nil no formato table.
+func fieldsFormatter(format string, names []string) (write formatter, flush func() error, err error) {
+	values := func(res APIResult) []string {
+		var out []string
+		for _, f := range selectFields(res, names) {
+			out = append(out, fmt.Sprint(f.value))
+		}
+		return out
+	}
+	switch format {
+	case "text":
+		write = func(w io.Writer, res APIResult) error {
+			for _, f := range selectFields(res, names) {
+				fmt.Fprintf(w, "%s %v\n", paint(w, colorLabel, tr(fieldLabels[f.name])+":"), f.value)
+			}
+			return nil
+		}
+	case "tsv":
+		write = func(w io.Writer, res APIResult) error {
+			v := values(res)
+			for i := range v {
+				v[i] = strings.ReplaceAll(v[i], "\t", " ")
+			}
+			_, err := fmt.Fprintln(w, strings.Join(v, "\t"))
+			return err
+		}
+	case "csv":
+		var once sync.Once
+		write = func(w io.Writer, res APIResult) error {
+			var err error
+			once.Do(func() { err = writeCSVRecord(w, names) })
+			if err != nil {
+				return err
+			}
+			return writeCSVRecord(w, values(res))
+		}
+	case "oneline":
+		write = func(w io.Writer, res APIResult) error {
+			var parts []string
+			for _, v := range values(res) {
+				if v != "" {
+					parts = append(parts, v)
+				}
+			}
+			_, err := fmt.Fprintln(w, strings.Join(parts, ", "))
+			return err
+		}
+	case "json", "ndjson":
+		write = func(w io.Writer, res APIResult) error {
+			data, err := fieldsJSON(selectFields(res, names))
+			if err != nil {
+				return err
+			}
+			if format == "json" {
+				var b bytes.Buffer
+				json.Indent(&b, data, "", "  ")
+				data = b.Bytes()
+			}
+			_, err = fmt.Fprintf(w, "%s\n", data)
+			return err
+		}
+	case "yaml", "xml":
+		write = func(w io.Writer, res APIResult) error {
+			n, err := fieldsNode(selectFields(res, names))
+			if err != nil {
+				return err
+			}
+			if format == "xml" {
+				return writeXMLNode(w, "address", n)
+			}
+			return writeYAMLNode(w, &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{n}})
+		}
+	case "table":
+		header := make([]string, len(names))
+		for i, name := range names {
+			header[i] = tr(fieldLabels[name])
+		}
+		write, flush = tableFormatter(header, values)
+	default:
+		return nil, nil, errors.New(tr("--fields não pode ser usado com --format %s", format))
+	}
+	return write, flush, nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// geoFeature é um ponto de uma FeatureCollection GeoJSON (RFC 7946).
+type geoFeature struct {
+	Type     string      `json:"type"`
+	Geometry geoPoint    `json:"geometry"`
+	Props    geoProperty `json:"properties"`
+}
+
+type geoPoint struct {
+	Type string `json:"type"`
+	// Coordinates segue a ordem do GeoJSON: longitude, latitude e, se
+	// houver, altitude.
+	Coordinates []float64 `json:"coordinates"`
+}
+
+type geoProperty struct {
+	Address
+	Source string `json:"source"`
+}
+
+// geojsonFormatter devolve um formatter que acumula como pontos os resultados
+// com coordenadas e a função que escreve a FeatureCollection no w da
+// primeira chamada. Os resultados sem coordenadas ficam de fora, com um aviso
+// na saída de erro.
+func geojsonFormatter() (write formatter, flush func() error) {
+	var out io.Writer
+	features := []geoFeature{}
+	write = func(w io.Writer, res APIResult) error {
+		out = w
+		loc := res.Addr.Location
+		if loc == nil {
+			fmt.Fprint(os.Stderr, tr("Aviso: CEP %s sem coordenadas, omitido do GeoJSON\n", res.Addr.CEP))
+			return nil
+		}
+		coords := []float64{loc.Longitude, loc.Latitude}
+		if loc.Altitude != 0 {
+			coords = append(coords, loc.Altitude)
+		}
+		addr := res.Addr
+		addr.Location = nil
+		features = append(features, geoFeature{
+			Type:     "Feature",
+			Geometry: geoPoint{Type: "Point", Coordinates: coords},
+			Props:    geoProperty{Address: addr, Source: res.Source},
+		})
+		return nil
+	}
+	flush = func() error {
+		if out == nil {
+			return nil
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Type     string       `json:"type"`
+			Features []geoFeature `json:"features"`
+		}{"FeatureCollection", features})
+	}
+	return write, flush
+}
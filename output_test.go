@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var sampleResult = APIResult{
+	Addr: Address{
+		CEP: "01001000", Street: "Praça da Sé", Complement: "lado ímpar", Neighborhood: "Sé",
+		City: "São Paulo", State: "SP", Country: "BR",
+		Location: &Location{Latitude: -23.5503, Longitude: -46.6339},
+	},
+	Source:  "ViaCEP",
+	Latency: 120 * time.Millisecond,
+}
+
+// render escreve results com o formatter de f, como a CLI faz.
+func render(t *testing.T, f *lookupFlags, results ...APIResult) string {
+	t.Helper()
+	write, err := f.formatter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	for _, res := range results {
+		if err := write(&b, res); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if f.flush != nil {
+		if err := f.flush(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return strings.ReplaceAll(b.String(), "\r\n", "\n")
+}
+
+// checkOutput confere a saída de f para dois resultados iguais.
+func checkOutput(t *testing.T, f *lookupFlags, want string) {
+	t.Helper()
+	if got := render(t, f, sampleResult, sampleResult); got != want {
+		t.Errorf("saída:\n%s\nquero:\n%s", got, want)
+	}
+}
+
+// checkFields confere os campos comuns aos formatos estruturados.
+func checkFields(t *testing.T, got map[string]any) {
+	t.Helper()
+	for key, want := range map[string]any{
+		"cep": "01001000", "city": "São Paulo", "source": "ViaCEP", "cache_hit": false,
+	} {
+		if got[key] != want {
+			t.Errorf("%s = %#v, quero %#v", key, got[key], want)
+		}
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	var got map[string]any
+	if err := json.Unmarshal([]byte(render(t, &lookupFlags{format: "json"}, sampleResult)), &got); err != nil {
+		t.Fatal(err)
+	}
+	checkFields(t, got)
+	if got["elapsed_ms"] != float64(120) {
+		t.Errorf("elapsed_ms = %v, quero 120", got["elapsed_ms"])
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	if _, err := (&lookupFlags{format: "pdf"}).formatter(); err == nil {
+		t.Error("formato desconhecido aceito")
+	}
+}
+
+func TestYAMLFormat(t *testing.T) {
+	var got map[string]any
+	if err := yaml.Unmarshal([]byte(render(t, &lookupFlags{format: "yaml"}, sampleResult)), &got); err != nil {
+		t.Fatal(err)
+	}
+	// O CEP precisa continuar sendo texto, com o zero à esquerda
+	checkFields(t, got)
+}
+
+func TestCSVFormat(t *testing.T) {
+	checkOutput(t, &lookupFlags{format: "csv"}, "cep,street,neighborhood,city,state,source,elapsed_ms,cache_hit\n"+
+		"01001000,Praça da Sé,Sé,São Paulo,SP,ViaCEP,120,false\n"+
+		"01001000,Praça da Sé,Sé,São Paulo,SP,ViaCEP,120,false\n")
+
+	res := APIResult{Addr: Address{CEP: "01001000", Street: `Rua "Nova", 1`}, Source: "ViaCEP"}
+	got := render(t, &lookupFlags{format: "csv"}, res)
+	if want := `01001000,"Rua ""Nova"", 1",,,,ViaCEP,0,false`; !strings.Contains(got, want) {
+		t.Errorf("saída:\n%s\nquero a linha %s", got, want)
+	}
+}
+
+func TestXMLFormat(t *testing.T) {
+	var got struct {
+		CEP      string  `xml:"cep"`
+		City     string  `xml:"city"`
+		Source   string  `xml:"source"`
+		Latitude float64 `xml:"location>latitude"`
+	}
+	if err := xml.Unmarshal([]byte(render(t, &lookupFlags{format: "xml"}, sampleResult)), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.CEP != "01001000" || got.City != "São Paulo" || got.Source != "ViaCEP" || got.Latitude != -23.5503 {
+		t.Errorf("xml = %+v", got)
+	}
+}
+
+func TestTemplateFormat(t *testing.T) {
+	tests := []struct{ template, want string }{
+		{"{{.City}}/{{.State}}", "São Paulo/SP\n"},
+		{"{{.Street}} {{.Source}}\n", "Praça da Sé ViaCEP\n"},
+	}
+	for _, tt := range tests {
+		if got := render(t, &lookupFlags{format: "text", template: tt.template}, sampleResult); got != tt.want {
+			t.Errorf("--template %q = %q, quero %q", tt.template, got, tt.want)
+		}
+	}
+	if _, err := (&lookupFlags{template: "{{.City"}).formatter(); err == nil {
+		t.Error("modelo inválido aceito")
+	}
+}
+
+func TestTableFormat(t *testing.T) {
+	checkOutput(t, &lookupFlags{format: "table"}, "CEP       Rua          Bairro  Cidade     Estado  Fonte\n"+
+		"01001000  Praça da Sé  Sé      São Paulo  SP      ViaCEP\n"+
+		"01001000  Praça da Sé  Sé      São Paulo  SP      ViaCEP\n")
+}
+
+func TestOneLineFormat(t *testing.T) {
+	checkOutput(t, &lookupFlags{format: "text", oneline: true}, "Praça da Sé, Sé, São Paulo - SP, 01001-000\n"+
+		"Praça da Sé, Sé, São Paulo - SP, 01001-000\n")
+}
+
+func TestFormatCEP(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"01310100", "01310-100"},
+		{"0131010", "0131010"},
+		{"SW1A1AA", "SW1A1AA"},
+	}
+	for _, tt := range tests {
+		if got := formatCEP(tt.in); got != tt.want {
+			t.Errorf("formatCEP(%q) = %q, quero %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLabelFormat(t *testing.T) {
+	checkOutput(t, &lookupFlags{format: "label"}, strings.Repeat("Praça da Sé - lado ímpar\nSé\nSão Paulo - SP\n01001-000\n", 2))
+}
+
+func TestNDJSONFormat(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(render(t, &lookupFlags{format: "ndjson"}, sampleResult, sampleResult)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("%d linhas, quero 2", len(lines))
+	}
+	for _, line := range lines {
+		var got map[string]any
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatal(err)
+		}
+		checkFields(t, got)
+	}
+}
+
+func TestFieldsFormat(t *testing.T) {
+	tests := []struct{ format, want string }{
+		{"csv", "cep,city\n01001000,São Paulo\n"},
+		{"tsv", "01001000\tSão Paulo\n"},
+		{"oneline", "01001000, São Paulo\n"},
+		{"ndjson", `{"cep":"01001000","city":"São Paulo"}` + "\n"},
+		{"text", "CEP: 01001000\nCidade: São Paulo\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := render(t, &lookupFlags{format: tt.format, fields: "cep,city"}, sampleResult); got != tt.want {
+				t.Errorf("saída %q, quero %q", got, tt.want)
+			}
+		})
+	}
+	for _, f := range []*lookupFlags{{format: "label", fields: "cep"}, {format: "json", fields: "cep,foo"}} {
+		if _, err := f.formatter(); err == nil {
+			t.Errorf("--format %s --fields %s aceito", f.format, f.fields)
+		}
+	}
+}
+
+func TestGeoJSONFormat(t *testing.T) {
+	var got struct {
+		Features []struct {
+			Geometry struct {
+				Coordinates []float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]any `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal([]byte(render(t, &lookupFlags{format: "geojson"}, sampleResult, sampleResult)), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Features) != 2 {
+		t.Fatalf("%d features, quero 2", len(got.Features))
+	}
+	// GeoJSON usa [longitude, latitude]
+	if c := got.Features[0].Geometry.Coordinates; len(c) != 2 || c[0] != -46.6339 || c[1] != -23.5503 {
+		t.Errorf("coordenadas = %v", c)
+	}
+}
+
+func TestVCardFormat(t *testing.T) {
+	checkOutput(t, &lookupFlags{format: "vcard"}, strings.Repeat("BEGIN:VCARD\nVERSION:4.0\nFN:Praça da Sé\\, São Paulo\n"+
+		"ADR:;lado ímpar\\, Sé;Praça da Sé;São Paulo;SP;01001-000;BR\nGEO:geo:-23.550300,-46.633900\nEND:VCARD\n", 2))
+}
+
+func TestTextFormat(t *testing.T) {
+	got := render(t, &lookupFlags{format: "text"}, sampleResult)
+	for _, line := range []string{"Resposta da ViaCEP:", "CEP: 01001000", "Rua: Praça da Sé", "Estado: SP (São Paulo)", "Latitude: -23.550300"} {
+		if !strings.Contains(got, line+"\n") {
+			t.Errorf("falta a linha %q em:\n%s", line, got)
+		}
+	}
+	quiet := render(t, &lookupFlags{format: "text", quiet: true}, sampleResult)
+	if strings.Contains(quiet, "Resposta da") {
+		t.Errorf("--quiet manteve o cabeçalho:\n%s", quiet)
+	}
+}
+
+func TestStateNameInStructuredFormats(t *testing.T) {
+	var got map[string]any
+	if err := json.Unmarshal([]byte(render(t, &lookupFlags{format: "json"}, sampleResult)), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["state_name"] != "São Paulo" {
+		t.Errorf("state_name = %v", got["state_name"])
+	}
+}
+
+func TestTSVFormat(t *testing.T) {
+	checkOutput(t, &lookupFlags{format: "tsv"}, strings.Repeat("01001000\tPraça da Sé\tlado ímpar\tSé\tSão Paulo\tSP\tBR\t\t\t\t\tViaCEP\n", 2))
+}
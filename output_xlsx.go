@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// xlsxColumns são as colunas da planilha de resultados de --output.
+var xlsxColumns = []string{"CEP", "Rua", "Complemento", "Bairro", "Cidade", "Estado", "Nome do estado", "Região", "DDD", "Latitude", "Longitude", "Fonte", "Tempo (ms)", "Cache", "Erro"}
+
+// checkOutputPath confere se --output aponta para um formato suportado.
+func checkOutputPath(path string) error {
+	if !strings.EqualFold(filepath.Ext(path), ".xlsx") {
+		return errors.New(tr("--output só aceita planilhas .xlsx: %s", path))
+	}
+	return nil
+}
+
+// writeXLSX grava em path uma planilha com uma linha por CEP de ceps, na
+// ordem da entrada, e uma segunda aba com o resumo do lote. As colunas têm
+// tipo: coordenadas e tempos são números e Cache é booleano.
+func writeXLSX(path string, ceps []string, lookups []Lookup) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	results, summary := tr("Resultados"), tr("Resumo")
+	if err := f.SetSheetName("Sheet1", results); err != nil {
+		return err
+	}
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+
+	header := make([]any, len(xlsxColumns))
+	for i, c := range xlsxColumns {
+		header[i] = tr(c)
+	}
+	if err := f.SetSheetRow(results, "A1", &header); err != nil {
+		return err
+	}
+	row := 2
+	for i, lookup := range lookups {
+		for _, res := range lookup.Results {
+			cell, _ := excelize.CoordinatesToCellName(1, row)
+			if err := f.SetSheetRow(results, cell, xlsxRow(ceps[i], res)); err != nil {
+				return err
+			}
+			row++
+		}
+	}
+	last, _ := excelize.ColumnNumberToName(len(xlsxColumns))
+	f.SetRowStyle(results, 1, 1, bold)
+	f.SetColWidth(results, "A", last, 16)
+	f.SetPanes(results, &excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"})
+	if row > 2 {
+		f.AutoFilter(results, fmt.Sprintf("A1:%s%d", last, row-1), nil)
+	}
+
+	if _, err := f.NewSheet(summary); err != nil {
+		return err
+	}
+	s := summarize(lookups)
+	for i, r := range [][]any{
+		{tr("Desfecho"), tr("CEPs")},
+		{tr("Total"), s.total},
+		{tr("Encontrados"), s.found},
+		{tr("Do cache"), s.cached},
+		{tr("Não encontrados"), s.notFound},
+		{tr("Inválidos"), s.invalid},
+		{tr("Com timeout"), s.timedOut},
+		{tr("Com erro"), s.failed},
+	} {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := f.SetSheetRow(summary, cell, &r); err != nil {
+			return err
+		}
+	}
+	f.SetRowStyle(summary, 1, 1, bold)
+	f.SetColWidth(summary, "A", "A", 20)
+	return f.SaveAs(path)
+}
+
+// xlsxRow devolve as células de xlsxColumns para o resultado res de cep.
+// Uma falha só preenche o CEP consultado e o erro.
+func xlsxRow(cep string, res APIResult) *[]any {
+	if res.Err != nil {
+		// As falhas de vários providers vêm uma por linha
+		msg := strings.NewReplacer(":\n", ": ", "\n", "; ").Replace(res.Err.Error())
+		row := []any{cep, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, res.Source, nil, nil, msg}
+		return &row
+	}
+	a := res.Addr
+	var lat, lng any
+	if a.Location != nil {
+		lat, lng = a.Location.Latitude, a.Location.Longitude
+	}
+	var elapsed any
+	if res.Latency > 0 {
+		elapsed = elapsedMS(res)
+	}
+	row := []any{a.CEP, a.Street, a.Complement, a.Neighborhood, a.City, a.State, stateNameFor(a), regionFor(a), a.DDD, lat, lng, res.Source, elapsed, res.CacheHit, nil}
+	return &row
+}
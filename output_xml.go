@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// xmlNames escolhe o nome dos elementos do formato xml: "json" usa as chaves
+// do formato json (elapsed_ms); "camel" usa lowerCamelCase (elapsedMs), a
+// convenção usual dos esquemas XSD.
+var xmlNames = "json"
+
+func setXMLNames(s string) error {
+	switch s {
+	case "json", "camel":
+		xmlNames = s
+		return nil
+	}
+	return errors.New(tr("nomes desconhecidos: %s (use json ou camel)", s))
+}
+
+// writeXML escreve o resultado como um elemento <address> com os mesmos
+// campos, na mesma ordem, do formato json.
+func writeXML(w io.Writer, res APIResult) error {
+	data, err := json.Marshal(newJSONResult(res))
+	if err != nil {
+		return err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	return writeXMLNode(w, "address", doc.Content[0])
+}
+
+// writeXMLNode escreve o mapeamento n como o elemento root.
+func writeXMLNode(w io.Writer, root string, n *yaml.Node) error {
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := encodeXML(enc, root, n); err != nil {
+		return err
+	}
+	if err := enc.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// encodeXML escreve n como o elemento name: objetos viram elementos filhos,
+// um por chave, listas repetem o elemento name, um por item, e valores viram
+// texto.
+func encodeXML(enc *xml.Encoder, name string, n *yaml.Node) error {
+	if n.Kind == yaml.SequenceNode {
+		for _, item := range n.Content {
+			if err := encodeXML(enc, name, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	start := xml.StartElement{Name: xml.Name{Local: xmlName(name)}}
+	if n.Kind != yaml.MappingNode {
+		return enc.EncodeElement(n.Value, start)
+	}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if err := encodeXML(enc, n.Content[i].Value, n.Content[i+1]); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
+
+// xmlName converte a chave json name conforme xmlNames.
+func xmlName(name string) string {
+	if xmlNames != "camel" {
+		return name
+	}
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
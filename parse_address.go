@@ -0,0 +1,184 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// parsedAddress é um endereço em texto livre separado em campos.
+type parsedAddress struct {
+	Address
+	// Number é o número do imóvel, ou zero se o texto não o tiver.
+	Number int
+}
+
+// addressSeparators separam as partes de um endereço escrito à mão: vírgulas,
+// " - " e a barra de "São Paulo/SP".
+var addressSeparators = strings.NewReplacer(" - ", ",", " – ", ",", "/", ",", ";", ",")
+
+// streetNumber é o número no fim do logradouro ("Av. Paulista 1578",
+// "Rua Augusta, nº 500").
+var streetNumber = regexp.MustCompile(`(?i)^(.*?)\s*(?:n[º°o.]?\s*)?(\d+)$`)
+
+// stateNamedCapitals são as UFs cuja capital tem o nome do estado: "São
+// Paulo" no fim do endereço é, ao mesmo tempo, a UF e a cidade.
+var stateNamedCapitals = map[string]bool{"SP": true, "RJ": true}
+
+// cepPattern é um CEP no meio do texto, que é ignorado: a busca é pelo
+// logradouro.
+var cepPattern = regexp.MustCompile(`\b\d{5}-?\d{3}\b`)
+
+// parseAddress separa um endereço como "Av. Paulista 1578, Bela Vista, São
+// Paulo SP" em logradouro, número, bairro, cidade e UF. A UF e a cidade são
+// procuradas do fim para o começo; o logradouro é a primeira parte e o
+// bairro, se houver, fica entre os dois.
+func parseAddress(s string) (parsedAddress, error) {
+	s = cepPattern.ReplaceAllString(s, "")
+	var parts []string
+	for _, p := range strings.Split(addressSeparators.Replace(s), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+
+	var a parsedAddress
+	if len(parts) > 0 {
+		last := parts[len(parts)-1]
+		if uf, rest, ok := cutState(last); ok {
+			a.State = uf
+			switch {
+			case rest != "":
+				parts[len(parts)-1] = rest
+			case stateNamedCapitals[uf] && !strings.EqualFold(last, uf):
+				// "Av. Paulista 1578, Bela Vista, São Paulo": o nome por
+				// extenso fica também como cidade
+			default:
+				parts = parts[:len(parts)-1]
+			}
+		}
+	}
+	if a.State == "" {
+		return a, errors.New(tr("não encontrei a UF no endereço (ex.: \"São Paulo SP\")"))
+	}
+	if len(parts) < 2 {
+		return a, errors.New(tr("o endereço precisa ter pelo menos o logradouro e a cidade, separados por vírgula"))
+	}
+	a.City = parts[len(parts)-1]
+	parts = parts[:len(parts)-1]
+
+	a.Street = parts[0]
+	parts = parts[1:]
+	// O número pode vir colado no logradouro ou como a parte seguinte
+	if m := streetNumber.FindStringSubmatch(a.Street); m != nil && m[1] != "" {
+		a.Street, a.Number = m[1], atoi(m[2])
+	} else if len(parts) > 0 {
+		if m := streetNumber.FindStringSubmatch(parts[0]); m != nil && m[1] == "" {
+			a.Number = atoi(m[2])
+			parts = parts[1:]
+		}
+	}
+	if len(parts) > 0 {
+		a.Neighborhood = parts[0]
+	}
+	return a, nil
+}
+
+// cutState separa a UF do fim de s, escrita como sigla ("São Paulo SP") ou
+// por extenso ("Minas Gerais"), e devolve o resto.
+func cutState(s string) (uf, rest string, ok bool) {
+	if i := strings.LastIndex(s, " "); i >= 0 || len(s) == 2 {
+		code := strings.ToUpper(s[i+1:])
+		if _, known := stateNames[code]; known {
+			return code, strings.TrimSpace(s[:max(i, 0)]), true
+		}
+	}
+	key := matchKey(s)
+	for code, name := range stateNames {
+		if key == matchKey(name) {
+			return code, "", true
+		}
+	}
+	return "", "", false
+}
+
+// describeParsed escreve a como foi entendido, para conferência.
+func describeParsed(a parsedAddress) string {
+	var parts []string
+	parts = append(parts, a.Street)
+	if a.Number > 0 {
+		parts = append(parts, tr("nº %d", a.Number))
+	}
+	if a.Neighborhood != "" {
+		parts = append(parts, a.Neighborhood)
+	}
+	return strings.Join(parts, ", ") + ", " + a.City + " - " + a.State
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// numberRange é o trecho de números de um CEP de logradouro, como o ViaCEP o
+// escreve no complemento: "até 610 - lado par", "de 1 a 999 - lado ímpar",
+// "de 612 ao fim".
+var numberRange = regexp.MustCompile(`de\s+(\d+)\s+(?:a|ao|ate)\s+(\d+|fim)|ate\s+(\d+)`)
+
+// numberMatch diz se o número n cabe no trecho descrito em complement: 1 se
+// cabe, 0 se não cabe e 0.5 se o complemento não fala de números.
+func numberMatch(n int, complement string) float64 {
+	key := strings.ToLower(stripAccents(complement))
+	known := false
+	if strings.Contains(key, "lado par") {
+		known = true
+		if n%2 != 0 {
+			return 0
+		}
+	}
+	if strings.Contains(key, "lado impar") {
+		known = true
+		if n%2 == 0 {
+			return 0
+		}
+	}
+	if m := numberRange.FindStringSubmatch(key); m != nil {
+		known = true
+		lo, hi := 0, -1
+		switch {
+		case m[1] != "":
+			lo = atoi(m[1])
+			if m[2] != "fim" {
+				hi = atoi(m[2])
+			}
+		default:
+			hi = atoi(m[3])
+		}
+		if n < lo || (hi >= 0 && n > hi) {
+			return 0
+		}
+	}
+	if !known {
+		return 0.5
+	}
+	return 1
+}
+
+// rankCandidates ordena os endereços pela confiança de que sejam o de want:
+// a média da semelhança dos campos e, com número, de numberMatch.
+func rankCandidates(want parsedAddress, addrs []Address) []APIResult {
+	results := make([]APIResult, len(addrs))
+	for i, a := range addrs {
+		matches, score := matchFields(want.Address, a)
+		if want.Number > 0 {
+			n := float64(len(matches))
+			score = (score*n + numberMatch(want.Number, a.Complement)) / (n + 1)
+		}
+		results[i] = APIResult{Addr: a, Source: "ViaCEP", Confidence: round2(score)}
+	}
+	slices.SortStableFunc(results, func(a, b APIResult) int { return cmp.Compare(b.Confidence, a.Confidence) })
+	return results
+}
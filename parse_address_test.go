@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		in   string
+		want parsedAddress
+	}{
+		{"Av. Paulista 1578, Bela Vista, São Paulo SP",
+			parsedAddress{Address: Address{Street: "Av. Paulista", Neighborhood: "Bela Vista", City: "São Paulo", State: "SP"}, Number: 1578}},
+		{"Av. Paulista 1578, Bela Vista, São Paulo",
+			parsedAddress{Address: Address{Street: "Av. Paulista", Neighborhood: "Bela Vista", City: "São Paulo", State: "SP"}, Number: 1578}},
+		{"Av. Paulista 1578, São Paulo",
+			parsedAddress{Address: Address{Street: "Av. Paulista", City: "São Paulo", State: "SP"}, Number: 1578}},
+		{"Av. Paulista, 1578 - Bela Vista, São Paulo/SP",
+			parsedAddress{Address: Address{Street: "Av. Paulista", Neighborhood: "Bela Vista", City: "São Paulo", State: "SP"}, Number: 1578}},
+		{"Rua Augusta, nº 500, Consolação, São Paulo, SP",
+			parsedAddress{Address: Address{Street: "Rua Augusta", Neighborhood: "Consolação", City: "São Paulo", State: "SP"}, Number: 500}},
+		{"Av. Atlântica, Copacabana, Rio de Janeiro",
+			parsedAddress{Address: Address{Street: "Av. Atlântica", Neighborhood: "Copacabana", City: "Rio de Janeiro", State: "RJ"}}},
+		{"Praça da Liberdade, Belo Horizonte, Minas Gerais",
+			parsedAddress{Address: Address{Street: "Praça da Liberdade", City: "Belo Horizonte", State: "MG"}}},
+		{"Rua da Praia 100, Porto Alegre RS 90010-000",
+			parsedAddress{Address: Address{Street: "Rua da Praia", City: "Porto Alegre", State: "RS"}, Number: 100}},
+	}
+	for _, tt := range tests {
+		got, err := parseAddress(tt.in)
+		if err != nil {
+			t.Errorf("parseAddress(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseAddress(%q) = %+v, quero %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseAddressErrors(t *testing.T) {
+	for _, in := range []string{"", "Av. Paulista 1578", "Av. Paulista, São Paulo XX", "Rua X, Bahia"} {
+		if got, err := parseAddress(in); err == nil {
+			t.Errorf("parseAddress(%q) = %+v, quero erro", in, got)
+		}
+	}
+}
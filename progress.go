@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// progress conta os CEPs resolvidos de um lote e desenha uma barra de
+// progresso numa única linha de w.
+type progress struct {
+	w     io.Writer
+	total int
+	start time.Time
+
+	mu                   sync.Mutex
+	found, failed, cache int
+}
+
+func (p *progress) add(l Lookup) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch {
+	case !l.OK():
+		p.failed++
+	case l.Results[0].CacheHit:
+		p.found++
+		p.cache++
+	default:
+		p.found++
+	}
+}
+
+// progressWidth é a largura da barra, em caracteres.
+const progressWidth = 30
+
+func (p *progress) draw() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	done := p.found + p.failed
+	filled := progressWidth * done / max(p.total, 1)
+	rate := float64(done) / max(time.Since(p.start).Seconds(), 0.001)
+	fmt.Fprint(p.w, tr("\r[%s%s] %d/%d · %d ok · %d falhas · %d do cache · %.1f/s ",
+		strings.Repeat("#", filled), strings.Repeat(".", progressWidth-filled),
+		done, p.total, p.found, p.failed, p.cache, rate))
+}
+
+// resolveWithProgress é como Resolver.ResolveAll, mas mostra em w o
+// andamento do lote enquanto ele roda.
+func resolveWithProgress(w io.Writer, r *Resolver, ceps []string, concurrency int) []Lookup {
+	p := &progress{w: w, total: len(ceps), start: time.Now()}
+	stop := make(chan struct{})
+	drawn := make(chan struct{})
+	go func() {
+		defer close(drawn)
+		ticker := time.NewTicker(200 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			p.draw()
+			select {
+			case <-ticker.C:
+			case <-stop:
+				p.draw()
+				fmt.Fprintln(w)
+				return
+			}
+		}
+	}()
+
+	lookups := make([]Lookup, len(ceps))
+	r.ResolveEach(context.Background(), ceps, concurrency, func(i int, l Lookup) {
+		lookups[i] = l
+		p.add(l)
+	})
+	close(stop)
+	<-drawn
+	return lookups
+}
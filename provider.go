@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Provider é uma fonte de consulta de CEP que participa da corrida.
+type Provider interface {
+	Name() string
+	Fetch(ctx context.Context, cep string) (Address, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []Provider
+)
+
+// RegisterProvider adiciona um provider ao registro. Normalmente chamado no
+// init() do arquivo que implementa o provider.
+func RegisterProvider(p Provider) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, p)
+}
+
+// Providers retorna os providers registrados, na ordem de registro.
+func Providers() []Provider {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return append([]Provider(nil), registry...)
+}
+
+// SelectProviders retorna os providers registrados cujos nomes estão em names,
+// na ordem informada. Uma lista vazia seleciona todos.
+func SelectProviders(names []string) ([]Provider, error) {
+	all := Providers()
+	if len(names) == 0 {
+		return all, nil
+	}
+
+	var selected []Provider
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		var found Provider
+		for _, p := range all {
+			if strings.EqualFold(p.Name(), name) {
+				found = p
+				break
+			}
+		}
+		if found == nil {
+			return nil, errors.New(tr("provider desconhecido: %s", name))
+		}
+		selected = append(selected, found)
+	}
+	if len(selected) == 0 {
+		return all, nil
+	}
+	return selected, nil
+}
+
+// getJSON faz um GET em url e decodifica o corpo da resposta em v.
+func getJSON(ctx context.Context, url string, v any) error {
+	return getJSONWithHeader(ctx, url, nil, v)
+}
+
+// getJSONWithHeader é como getJSON, mas envia os cabeçalhos extras em header.
+func getJSONWithHeader(ctx context.Context, url string, header http.Header, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// HTTPStatusError é uma resposta HTTP com status de erro. Status 5xx são
+// considerados transitórios e podem ser repetidos (veja isTransient); 4xx
+// são definitivos.
+type HTTPStatusError struct {
+	Code   int
+	Status string
+	// RetryAfter é a espera pedida pelo cabeçalho Retry-After, se houver.
+	RetryAfter time.Duration
+}
+
+func (e *HTTPStatusError) Error() string { return tr("resposta HTTP %s", e.Status) }
+
+// checkStatus valida o status de resp antes de o corpo ser decodificado.
+func checkStatus(resp *http.Response) error {
+	switch {
+	case resp.StatusCode < 400:
+		return nil
+	case resp.StatusCode == http.StatusNotFound:
+		// BrasilAPI, OpenCEP, Postmon e outros respondem 404 para CEPs inexistentes
+		return ErrNotFound
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return &HTTPStatusError{Code: resp.StatusCode, Status: resp.Status,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+	case resp.StatusCode == http.StatusBadRequest:
+		return fmt.Errorf("%w: %w", ErrInvalidCEP, &HTTPStatusError{Code: resp.StatusCode, Status: resp.Status})
+	default:
+		return &HTTPStatusError{Code: resp.StatusCode, Status: resp.Status}
+	}
+}
+
+// parseRetryAfter interpreta o cabeçalho Retry-After, em segundos ou como
+// data HTTP. Um valor ausente ou inválido resulta em um segundo.
+func parseRetryAfter(v string) time.Duration {
+	if secs, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return time.Second
+}
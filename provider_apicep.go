@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+type ApiCEPResponse struct {
+	Status   int    `json:"status"`
+	OK       bool   `json:"ok"`
+	Code     string `json:"code"`
+	State    string `json:"state"`
+	City     string `json:"city"`
+	District string `json:"district"`
+	Address  string `json:"address"`
+}
+
+type apiCEPProvider struct{}
+
+func init() { RegisterProvider(apiCEPProvider{}) }
+
+func (apiCEPProvider) Name() string { return "ApiCEP" }
+
+func (apiCEPProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	// A ApiCEP só aceita o CEP no formato 00000-000
+	if len(cep) == 8 {
+		cep = cep[:5] + "-" + cep[5:]
+	}
+	url := fmt.Sprintf("https://cdn.apicep.com/file/apicep/%s.json", cep)
+
+	var a ApiCEPResponse
+	if err := getJSON(ctx, url, &a); err != nil {
+		return Address{}, err
+	}
+	switch {
+	case a.Status == 400:
+		return Address{}, ErrInvalidCEP
+	case a.Status == 404 || !a.OK:
+		return Address{}, ErrNotFound
+	}
+
+	return Address{
+		CEP:          a.Code,
+		Street:       a.Address,
+		Neighborhood: a.District,
+		City:         a.City,
+		State:        a.State,
+	}, nil
+}
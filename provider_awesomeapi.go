@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+type AwesomeAPIResponse struct {
+	CEP      string `json:"cep"`
+	Address  string `json:"address"`
+	District string `json:"district"`
+	City     string `json:"city"`
+	State    string `json:"state"`
+	Lat      string `json:"lat"`
+	Lng      string `json:"lng"`
+	DDD      string `json:"ddd"`
+	CityIBGE string `json:"city_ibge"`
+}
+
+type awesomeAPIProvider struct{}
+
+func init() { RegisterProvider(awesomeAPIProvider{}) }
+
+func (awesomeAPIProvider) Name() string { return "AwesomeAPI" }
+
+func (awesomeAPIProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	url := fmt.Sprintf("https://cep.awesomeapi.com.br/json/%s", cep)
+
+	var a AwesomeAPIResponse
+	if err := getJSON(ctx, url, &a); err != nil {
+		return Address{}, err
+	}
+
+	addr := Address{
+		CEP:          a.CEP,
+		Street:       a.Address,
+		Neighborhood: a.District,
+		City:         a.City,
+		State:        a.State,
+		DDD:          a.DDD,
+		IBGE:         a.CityIBGE,
+	}
+	lat, errLat := strconv.ParseFloat(a.Lat, 64)
+	lng, errLng := strconv.ParseFloat(a.Lng, 64)
+	if errLat == nil && errLng == nil {
+		addr.Location = &Location{Latitude: lat, Longitude: lng}
+	}
+	return addr, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+type BrasilAPIResponse struct {
+	CEP          string `json:"cep"`
+	State        string `json:"state"`
+	City         string `json:"city"`
+	Neighborhood string `json:"neighborhood"`
+	Street       string `json:"street"`
+	Location     struct {
+		Coordinates struct {
+			Latitude  coordinate `json:"latitude"`
+			Longitude coordinate `json:"longitude"`
+		} `json:"coordinates"`
+	} `json:"location"`
+}
+
+// coordinate aceita coordenadas vindas como número ou como string, já que a
+// BrasilAPI v2 repassa o formato de cada serviço de origem. Valores ausentes
+// ou vazios ficam como nil.
+type coordinate struct {
+	value *float64
+}
+
+func (c *coordinate) UnmarshalJSON(data []byte) error {
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	switch v := raw.(type) {
+	case float64:
+		c.value = &v
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			c.value = &f
+		}
+	}
+	return nil
+}
+
+type brasilAPIProvider struct{}
+
+func init() { RegisterProvider(brasilAPIProvider{}) }
+
+func (brasilAPIProvider) Name() string { return "BrasilAPI" }
+
+func (brasilAPIProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	url := fmt.Sprintf("https://brasilapi.com.br/api/cep/v2/%s", cep)
+
+	var r BrasilAPIResponse
+	if err := getJSON(ctx, url, &r); err != nil {
+		return Address{}, err
+	}
+
+	addr := Address{
+		CEP:          r.CEP,
+		Street:       r.Street,
+		Neighborhood: r.Neighborhood,
+		City:         r.City,
+		State:        r.State,
+	}
+	if lat, lng := r.Location.Coordinates.Latitude.value, r.Location.Coordinates.Longitude.value; lat != nil && lng != nil {
+		addr.Location = &Location{Latitude: *lat, Longitude: *lng}
+	}
+	return addr, nil
+}
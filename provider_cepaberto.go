@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+type CEPAbertoResponse struct {
+	CEP         string  `json:"cep"`
+	Logradouro  string  `json:"logradouro"`
+	Complemento string  `json:"complemento"`
+	Bairro      string  `json:"bairro"`
+	Latitude    string  `json:"latitude"`
+	Longitude   string  `json:"longitude"`
+	Altitude    float64 `json:"altitude"`
+	Cidade      struct {
+		Nome string `json:"nome"`
+	} `json:"cidade"`
+	Estado struct {
+		Sigla string `json:"sigla"`
+	} `json:"estado"`
+}
+
+type cepAbertoProvider struct {
+	token string
+}
+
+// O CEP Aberto exige um token; sem ele o provider não é registrado.
+func init() {
+	if token := os.Getenv("CEPABERTO_TOKEN"); token != "" {
+		RegisterProvider(cepAbertoProvider{token: token})
+	}
+}
+
+func (cepAbertoProvider) Name() string { return "CEPAberto" }
+
+func (p cepAbertoProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	url := fmt.Sprintf("https://www.cepaberto.com/api/v3/cep?cep=%s", cep)
+	header := http.Header{"Authorization": {"Token token=" + p.token}}
+
+	var c CEPAbertoResponse
+	if err := getJSONWithHeader(ctx, url, header, &c); err != nil {
+		return Address{}, err
+	}
+	// CEPs inexistentes vêm como {} com status 200
+	if c.CEP == "" {
+		return Address{}, ErrNotFound
+	}
+
+	addr := Address{
+		CEP:          c.CEP,
+		Street:       c.Logradouro,
+		Complement:   c.Complemento,
+		Neighborhood: c.Bairro,
+		City:         c.Cidade.Nome,
+		State:        c.Estado.Sigla,
+	}
+	lat, errLat := strconv.ParseFloat(c.Latitude, 64)
+	lng, errLng := strconv.ParseFloat(c.Longitude, 64)
+	if errLat == nil && errLng == nil {
+		addr.Location = &Location{Latitude: lat, Longitude: lng, Altitude: c.Altitude}
+	}
+	return addr, nil
+}
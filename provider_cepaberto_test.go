@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestCEPAbertoFetch(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		wantCity string
+		wantErr  error
+	}{
+		{"encontrado", http.StatusOK, `{"cep":"01001000","logradouro":"Praça da Sé","cidade":{"nome":"São Paulo"},"estado":{"sigla":"SP"}}`, "São Paulo", nil},
+		{"inexistente vem como {}", http.StatusOK, `{}`, "", ErrNotFound},
+		{"404", http.StatusNotFound, ``, "", ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serveHTTP(t, tt.status, tt.body)
+			addr, err := cepAbertoProvider{token: "x"}.Fetch(context.Background(), "01001000")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, quero %v", err, tt.wantErr)
+			}
+			if addr.City != tt.wantCity {
+				t.Errorf("cidade = %q, quero %q", addr.City, tt.wantCity)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const correiosURL = "https://apps.correios.com.br/SigepMasterJPA/AtendeClienteService/AtendeCliente"
+
+type CorreiosResponse struct {
+	Body struct {
+		Fault *struct {
+			FaultString string `xml:"faultstring"`
+		} `xml:"Fault"`
+		ConsultaCEPResponse struct {
+			Return struct {
+				CEP          string `xml:"cep"`
+				End          string `xml:"end"`
+				Complemento2 string `xml:"complemento2"`
+				Bairro       string `xml:"bairro"`
+				Cidade       string `xml:"cidade"`
+				UF           string `xml:"uf"`
+			} `xml:"return"`
+		} `xml:"consultaCEPResponse"`
+	} `xml:"Body"`
+}
+
+type correiosProvider struct{}
+
+func init() { RegisterProvider(correiosProvider{}) }
+
+func (correiosProvider) Name() string { return "Correios" }
+
+// correiosEnvelope monta o envelope SOAP da operação consultaCEP.
+func correiosEnvelope(cep string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" ` +
+		`xmlns:cli="http://cliente.bean.master.sigep.bsb.correios.com.br/">` +
+		`<soapenv:Header/><soapenv:Body><cli:consultaCEP><cep>`)
+	if err := xml.EscapeText(&buf, []byte(cep)); err != nil {
+		return nil, err
+	}
+	buf.WriteString(`</cep></cli:consultaCEP></soapenv:Body></soapenv:Envelope>`)
+	return buf.Bytes(), nil
+}
+
+func (correiosProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	envelope, err := correiosEnvelope(cep)
+	if err != nil {
+		return Address{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, correiosURL, bytes.NewReader(envelope))
+	if err != nil {
+		return Address{}, err
+	}
+	req.Header.Set("Content-Type", "text/xml; charset=utf-8")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return Address{}, err
+	}
+	defer resp.Body.Close()
+
+	// As falhas SOAP vêm com status 500, então o corpo é lido antes do
+	// status; uma página de erro de um proxy não é XML e cai no checkStatus
+	var c CorreiosResponse
+	if err := xml.NewDecoder(resp.Body).Decode(&c); err != nil {
+		if statusErr := checkStatus(resp); statusErr != nil {
+			return Address{}, statusErr
+		}
+		return Address{}, err
+	}
+	if f := c.Body.Fault; f != nil {
+		msg := strings.ToUpper(f.FaultString)
+		switch {
+		case strings.Contains(msg, "NAO ENCONTRADO"), strings.Contains(msg, "NÃO ENCONTRADO"):
+			return Address{}, fmt.Errorf("%w: %s", ErrNotFound, f.FaultString)
+		case strings.Contains(msg, "INVALIDO"), strings.Contains(msg, "INVÁLIDO"):
+			return Address{}, fmt.Errorf("%w: %s", ErrInvalidCEP, f.FaultString)
+		}
+		return Address{}, errors.New(f.FaultString)
+	}
+	if err := checkStatus(resp); err != nil {
+		return Address{}, err
+	}
+
+	r := c.Body.ConsultaCEPResponse.Return
+	return Address{
+		CEP:          r.CEP,
+		Street:       r.End,
+		Complement:   r.Complemento2,
+		Neighborhood: r.Bairro,
+		City:         r.Cidade,
+		State:        r.UF,
+	}, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func correiosBody(inner string) string {
+	return `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>` + inner + `</soap:Body></soap:Envelope>`
+}
+
+func TestCorreiosFetch(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantCity   string
+		wantErr    error
+		wantStatus int
+	}{
+		{"encontrado", http.StatusOK, correiosBody(`<ns2:consultaCEPResponse xmlns:ns2="x"><return><cep>01001000</cep><cidade>São Paulo</cidade><uf>SP</uf></return></ns2:consultaCEPResponse>`), "São Paulo", nil, 0},
+		{"falha SOAP de CEP inexistente", http.StatusInternalServerError, correiosBody(`<soap:Fault><faultstring>CEP NAO ENCONTRADO</faultstring></soap:Fault>`), "", ErrNotFound, 0},
+		{"falha SOAP de CEP inválido", http.StatusInternalServerError, correiosBody(`<soap:Fault><faultstring>CEP INVÁLIDO</faultstring></soap:Fault>`), "", ErrInvalidCEP, 0},
+		{"página HTML de proxy", http.StatusBadGateway, `<html><body>Bad Gateway</body></html>`, "", nil, http.StatusBadGateway},
+		{"503 sem corpo", http.StatusServiceUnavailable, ``, "", nil, http.StatusServiceUnavailable},
+		{"XML válido com status 403", http.StatusForbidden, correiosBody(``), "", nil, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serveHTTP(t, tt.status, tt.body)
+			addr, err := correiosProvider{}.Fetch(context.Background(), "01001000")
+			if tt.wantStatus != 0 {
+				var statusErr *HTTPStatusError
+				if !errors.As(err, &statusErr) || statusErr.Code != tt.wantStatus {
+					t.Fatalf("err = %v, quero HTTPStatusError %d", err, tt.wantStatus)
+				}
+				if transient := isTransient(err); transient != (tt.wantStatus >= 500) {
+					t.Errorf("isTransient = %v", transient)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, quero %v", err, tt.wantErr)
+			}
+			if addr.City != tt.wantCity {
+				t.Errorf("cidade = %q, quero %q", addr.City, tt.wantCity)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ExecProviderConfig declara um plugin executável no arquivo de configuração:
+//
+//	plugins:
+//	  - name: Interno
+//	    command: /usr/local/bin/cep-interno
+//	    args: ["--json"]
+//	    input: stdin
+//
+// O executável recebe o CEP pela entrada padrão (input: stdin, o padrão) ou
+// como último argumento (input: argv) e deve escrever um Address em JSON na
+// saída padrão.
+type ExecProviderConfig struct {
+	Name    string   `yaml:"name"`
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args"`
+	Input   string   `yaml:"input"`
+}
+
+type execProvider struct {
+	cfg ExecProviderConfig
+}
+
+// newExecProvider valida a configuração e cria o provider.
+func newExecProvider(cfg ExecProviderConfig) (Provider, error) {
+	if cfg.Name == "" {
+		return nil, errors.New(tr("plugin sem nome na configuração"))
+	}
+	if cfg.Command == "" {
+		return nil, errors.New(tr("plugin %s: command é obrigatório", cfg.Name))
+	}
+	switch cfg.Input {
+	case "":
+		cfg.Input = "stdin"
+	case "stdin", "argv":
+	default:
+		return nil, errors.New(tr("plugin %s: input deve ser stdin ou argv", cfg.Name))
+	}
+	return execProvider{cfg: cfg}, nil
+}
+
+func (p execProvider) Name() string { return p.cfg.Name }
+
+func (p execProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	args := append([]string(nil), p.cfg.Args...)
+	if p.cfg.Input == "argv" {
+		args = append(args, cep)
+	}
+
+	cmd := exec.CommandContext(ctx, p.cfg.Command, args...)
+	if p.cfg.Input == "stdin" {
+		cmd.Stdin = strings.NewReader(cep + "\n")
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return Address{}, fmt.Errorf("%w: %s", err, msg)
+		}
+		return Address{}, err
+	}
+
+	var addr Address
+	if err := json.Unmarshal(stdout.Bytes(), &addr); err != nil {
+		return Address{}, err
+	}
+	// Um plugin que não achou o CEP pode escrever só {}
+	if addr.empty() {
+		return Address{}, ErrNotFound
+	}
+	return addr, nil
+}
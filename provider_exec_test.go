@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestExecProviderFetch(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		wantCity string
+		wantErr  error
+	}{
+		{"encontrado", `read cep; echo "{\"cep\":\"$cep\",\"city\":\"São Paulo\",\"state\":\"SP\"}"`, "São Paulo", nil},
+		{"objeto vazio", `echo '{}'`, "", ErrNotFound},
+		{"só o CEP de volta", `read cep; echo "{\"cep\":\"$cep\"}"`, "", ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := newExecProvider(ExecProviderConfig{Name: "Shell", Command: "sh", Args: []string{"-c", tt.script}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			addr, err := p.Fetch(context.Background(), "01001000")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, quero %v", err, tt.wantErr)
+			}
+			if addr.City != tt.wantCity {
+				t.Errorf("cidade = %q, quero %q", addr.City, tt.wantCity)
+			}
+		})
+	}
+}
+
+func TestExecProviderFailure(t *testing.T) {
+	p, _ := newExecProvider(ExecProviderConfig{Name: "Shell", Command: "sh", Args: []string{"-c", "echo falhou >&2; exit 1"}})
+	if _, err := p.Fetch(context.Background(), "01001000"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, quero a falha do plugin", err)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Base de CEPs embutida no binário. Outras bases CSV podem ser somadas a ela
+// com a chave offline_dataset do arquivo de configuração.
+//
+//go:embed data/ceps.csv
+var embeddedDataset string
+
+var errOfflineMiss = fmt.Errorf("%w na base offline", ErrNotFound)
+
+type offlineProvider struct {
+	ceps map[string]Address
+}
+
+var offline = &offlineProvider{ceps: map[string]Address{}}
+
+func init() {
+	if err := offline.load(strings.NewReader(embeddedDataset)); err != nil {
+		panic(fmt.Sprintf("base offline embutida inválida: %v", err))
+	}
+	RegisterProvider(offline)
+}
+
+// loadFile soma à base offline os CEPs do arquivo CSV em path.
+func (o *offlineProvider) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := o.load(f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// load lê um CSV com cabeçalho cep,street,complement,neighborhood,city,state.
+func (o *offlineProvider) load(r io.Reader) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 6
+
+	if _, err := cr.Read(); err != nil {
+		return err
+	}
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		o.ceps[rec[0]] = Address{
+			CEP:          rec[0],
+			Street:       rec[1],
+			Complement:   rec[2],
+			Neighborhood: rec[3],
+			City:         rec[4],
+			State:        rec[5],
+		}
+	}
+}
+
+func (*offlineProvider) Name() string { return "Offline" }
+
+func (o *offlineProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	if addr, ok := o.ceps[cep]; ok {
+		return addr, nil
+	}
+	return Address{}, errOfflineMiss
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+type OpenCEPResponse struct {
+	CEP         string `json:"cep"`
+	Logradouro  string `json:"logradouro"`
+	Complemento string `json:"complemento"`
+	Bairro      string `json:"bairro"`
+	Localidade  string `json:"localidade"`
+	UF          string `json:"uf"`
+}
+
+type openCEPProvider struct{}
+
+func init() { RegisterProvider(openCEPProvider{}) }
+
+func (openCEPProvider) Name() string { return "OpenCEP" }
+
+func (openCEPProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	url := fmt.Sprintf("https://opencep.com/v1/%s", cep)
+
+	var o OpenCEPResponse
+	if err := getJSON(ctx, url, &o); err != nil {
+		return Address{}, err
+	}
+
+	return Address{
+		CEP:          o.CEP,
+		Street:       o.Logradouro,
+		Complement:   o.Complemento,
+		Neighborhood: o.Bairro,
+		City:         o.Localidade,
+		State:        o.UF,
+	}, nil
+}
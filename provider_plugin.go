@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"plugin"
+)
+
+// pluginProvider é o contrato esperado do valor retornado pelo símbolo
+// NewProvider de um plugin Go (.so). Como o plugin não pode importar os tipos
+// deste pacote main, Fetch devolve os campos do Address num mapa cujas chaves
+// são as mesmas de addressFields (cep, street, city, ...).
+//
+// Exemplo de plugin, compilado com `go build -buildmode=plugin`:
+//
+//	package main
+//
+//	type meuProvider struct{}
+//
+//	func (meuProvider) Name() string { return "Privado" }
+//	func (meuProvider) Fetch(ctx context.Context, cep string) (map[string]string, error) { ... }
+//
+//	func NewProvider() any { return meuProvider{} }
+type pluginProvider interface {
+	Name() string
+	Fetch(ctx context.Context, cep string) (map[string]string, error)
+}
+
+type goPluginProvider struct {
+	p pluginProvider
+}
+
+func (g goPluginProvider) Name() string { return g.p.Name() }
+
+func (g goPluginProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	fields, err := g.p.Fetch(ctx, cep)
+	if err != nil {
+		return Address{}, err
+	}
+
+	var addr Address
+	for field, value := range fields {
+		if set, ok := addressFields[field]; ok {
+			*set(&addr) = value
+		}
+	}
+	// Um mapa vazio, ou só com chaves desconhecidas, é um CEP não encontrado
+	if addr.empty() {
+		return Address{}, ErrNotFound
+	}
+	return addr, nil
+}
+
+// loadPlugins abre todos os arquivos .so de dir e devolve os providers
+// criados pelos respectivos símbolos NewProvider.
+func loadPlugins(dir string) ([]Provider, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.so"))
+	if err != nil {
+		return nil, err
+	}
+
+	var providers []Provider
+	for _, path := range paths {
+		plug, err := plugin.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %s: %w", path, err)
+		}
+		sym, err := plug.Lookup("NewProvider")
+		if err != nil {
+			return nil, fmt.Errorf("plugin %s: %w", path, err)
+		}
+		newProvider, ok := sym.(func() any)
+		if !ok {
+			return nil, errors.New(tr("plugin %s: NewProvider deve ter a assinatura func() any", path))
+		}
+		p, ok := newProvider().(pluginProvider)
+		if !ok {
+			return nil, errors.New(tr("plugin %s: NewProvider não retornou um provider válido", path))
+		}
+		providers = append(providers, goPluginProvider{p: p})
+	}
+	return providers, nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type mapPlugin map[string]string
+
+func (mapPlugin) Name() string { return "Mapa" }
+
+func (m mapPlugin) Fetch(ctx context.Context, cep string) (map[string]string, error) {
+	return m, nil
+}
+
+func TestGoPluginProviderFetch(t *testing.T) {
+	tests := []struct {
+		name     string
+		fields   map[string]string
+		wantCity string
+		wantErr  error
+	}{
+		{"encontrado", map[string]string{"cep": "01001-000", "city": "São Paulo", "bairro": "Sé"}, "São Paulo", nil},
+		{"mapa vazio", map[string]string{}, "", ErrNotFound},
+		{"só chaves desconhecidas", map[string]string{"cidade": "São Paulo"}, "", ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, err := goPluginProvider{p: mapPlugin(tt.fields)}.Fetch(context.Background(), "01001000")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, quero %v", err, tt.wantErr)
+			}
+			if addr.City != tt.wantCity {
+				t.Errorf("cidade = %q, quero %q", addr.City, tt.wantCity)
+			}
+		})
+	}
+}
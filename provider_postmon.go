@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+type PostmonResponse struct {
+	CEP         string `json:"cep"`
+	Logradouro  string `json:"logradouro"`
+	Complemento string `json:"complemento"`
+	Bairro      string `json:"bairro"`
+	Cidade      string `json:"cidade"`
+	Estado      string `json:"estado"`
+}
+
+type postmonProvider struct{}
+
+func init() { RegisterProvider(postmonProvider{}) }
+
+func (postmonProvider) Name() string { return "Postmon" }
+
+func (postmonProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	url := fmt.Sprintf("https://api.postmon.com.br/v1/cep/%s", cep)
+
+	var p PostmonResponse
+	if err := getJSON(ctx, url, &p); err != nil {
+		return Address{}, err
+	}
+
+	return Address{
+		CEP:          p.CEP,
+		Street:       p.Logradouro,
+		Complement:   p.Complemento,
+		Neighborhood: p.Bairro,
+		City:         p.Cidade,
+		State:        p.Estado,
+	}, nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// TemplateProviderConfig declara um provider genérico no arquivo de
+// configuração, por exemplo:
+//
+//	providers:
+//	  - name: Espelho
+//	    url: https://cep.interno/api/{cep}
+//	    headers:
+//	      Authorization: Bearer xyz
+//	    fields:
+//	      street: endereco.logradouro
+//	      city: endereco.cidade
+//	      state: uf
+//
+// Em fields, a chave é o campo do Address e o valor é o caminho (separado
+// por pontos) no JSON de resposta.
+type TemplateProviderConfig struct {
+	Name    string            `yaml:"name"`
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+	Fields  map[string]string `yaml:"fields"`
+}
+
+type templateProvider struct {
+	cfg TemplateProviderConfig
+}
+
+// newTemplateProvider valida a configuração e cria o provider.
+func newTemplateProvider(cfg TemplateProviderConfig) (Provider, error) {
+	if cfg.Name == "" {
+		return nil, errors.New(tr("provider sem nome na configuração"))
+	}
+	if !strings.Contains(cfg.URL, "{cep}") {
+		return nil, errors.New(tr("provider %s: url deve conter {cep}", cfg.Name))
+	}
+	for field := range cfg.Fields {
+		if _, ok := addressFields[field]; !ok {
+			return nil, errors.New(tr("provider %s: campo desconhecido %q", cfg.Name, field))
+		}
+	}
+	return templateProvider{cfg: cfg}, nil
+}
+
+func (p templateProvider) Name() string { return p.cfg.Name }
+
+func (p templateProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	u := strings.ReplaceAll(p.cfg.URL, "{cep}", url.PathEscape(cep))
+	header := http.Header{}
+	for k, v := range p.cfg.Headers {
+		header.Set(k, v)
+	}
+
+	var body any
+	if err := getJSONWithHeader(ctx, u, header, &body); err != nil {
+		return Address{}, err
+	}
+
+	addr := Address{}
+	found := false
+	for field, path := range p.cfg.Fields {
+		value := lookupPath(body, path)
+		*addressFields[field](&addr) = value
+		found = found || value != ""
+	}
+	// Uma resposta sem nenhum dos campos mapeados é a de um CEP inexistente
+	if !found {
+		return Address{}, ErrNotFound
+	}
+	if addr.CEP == "" {
+		addr.CEP = cep
+	}
+	return addr, nil
+}
+
+// lookupPath percorre v seguindo path ("a.b.0.c") e devolve o valor como
+// string, ou "" se o caminho não existir.
+func lookupPath(v any, path string) string {
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]any:
+			v = node[key]
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return ""
+			}
+			v = node[i]
+		default:
+			return ""
+		}
+	}
+
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		b, _ := json.Marshal(val)
+		return string(b)
+	}
+}
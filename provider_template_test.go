@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestTemplateProviderFetch(t *testing.T) {
+	p, err := newTemplateProvider(TemplateProviderConfig{
+		Name:   "Espelho",
+		URL:    "http://cep.interno/api/{cep}",
+		Fields: map[string]string{"street": "endereco.logradouro", "city": "endereco.cidade", "state": "uf"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    Address
+		wantErr error
+	}{
+		{"encontrado", http.StatusOK, `{"endereco":{"logradouro":"Praça da Sé","cidade":"São Paulo"},"uf":"SP"}`,
+			Address{CEP: "01001000", Street: "Praça da Sé", City: "São Paulo", State: "SP"}, nil},
+		{"resposta vazia", http.StatusOK, `{}`, Address{}, ErrNotFound},
+		{"campos nulos", http.StatusOK, `{"endereco":null,"uf":null}`, Address{}, ErrNotFound},
+		{"404", http.StatusNotFound, ``, Address{}, ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serveHTTP(t, tt.status, tt.body)
+			got, err := p.Fetch(context.Background(), "01001000")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, quero %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("endereço = %+v, quero %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupPath(t *testing.T) {
+	body := map[string]any{
+		"a":     map[string]any{"b": []any{map[string]any{"c": "x"}}},
+		"n":     float64(12.5),
+		"ok":    true,
+		"lista": []any{"p", "q"},
+	}
+	tests := []struct{ path, want string }{
+		{"a.b.0.c", "x"},
+		{"a.b.1.c", ""},
+		{"a.x", ""},
+		{"n", "12.5"},
+		{"ok", "true"},
+		{"lista", `["p","q"]`},
+		{"lista.-1", ""},
+	}
+	for _, tt := range tests {
+		if got := lookupPath(body, tt.path); got != tt.want {
+			t.Errorf("lookupPath(%q) = %q, quero %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serveHTTP faz http.DefaultClient responder a todas as requisições, até o
+// fim do teste, com status e body.
+func serveHTTP(t *testing.T, status int, body string) {
+	t.Helper()
+	old := http.DefaultClient.Transport
+	http.DefaultClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: status,
+			Status:     http.StatusText(status),
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	})
+	t.Cleanup(func() { http.DefaultClient.Transport = old })
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// stubProvider responde sempre com addr e err, depois de delay.
+type stubProvider struct {
+	name  string
+	addr  Address
+	err   error
+	delay time.Duration
+}
+
+func (p stubProvider) Name() string { return p.name }
+
+func (p stubProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	t := time.NewTimer(p.delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return p.addr, p.err
+	case <-ctx.Done():
+		return Address{}, ctx.Err()
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		value    string
+		min, max time.Duration
+	}{
+		{"segundos", "120", 120 * time.Second, 120 * time.Second},
+		{"zero", "0", 0, 0},
+		{"com espaços", " 5 ", 5 * time.Second, 5 * time.Second},
+		{"data HTTP", now.Add(30 * time.Second).UTC().Format(http.TimeFormat), 28 * time.Second, 30 * time.Second},
+		{"data no passado", now.Add(-time.Hour).UTC().Format(http.TimeFormat), 0, 0},
+		{"ausente", "", time.Second, time.Second},
+		{"negativo", "-3", time.Second, time.Second},
+		{"inválido", "logo", time.Second, time.Second},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value); got < tt.min || got > tt.max {
+			t.Errorf("%s: parseRetryAfter(%q) = %v, quero entre %v e %v", tt.name, tt.value, got, tt.min, tt.max)
+		}
+	}
+}
+
+func TestCheckStatusRetryAfter(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests", Header: http.Header{"Retry-After": {"7"}}}
+	var statusErr *HTTPStatusError
+	if err := checkStatus(resp); !errors.As(err, &statusErr) || statusErr.RetryAfter != 7*time.Second {
+		t.Fatalf("checkStatus = %v, quero HTTPStatusError com RetryAfter de 7s", err)
+	}
+}
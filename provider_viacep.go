@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+type ViaCEPResponse struct {
+	CEP         string `json:"cep"`
+	Logradouro  string `json:"logradouro"`
+	Complemento string `json:"complemento"`
+	Bairro      string `json:"bairro"`
+	Localidade  string `json:"localidade"`
+	UF          string `json:"uf"`
+	IBGE        string `json:"ibge"`
+	GIA         string `json:"gia"`
+	DDD         string `json:"ddd"`
+	SIAFI       string `json:"siafi"`
+	// Erro vem como true (ou "true", em versões recentes da API) para CEPs
+	// inexistentes, com status 200.
+	Erro any `json:"erro"`
+}
+
+type viaCEPProvider struct{}
+
+func init() { RegisterProvider(viaCEPProvider{}) }
+
+func (viaCEPProvider) Name() string { return "ViaCEP" }
+
+func (viaCEPProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	url := fmt.Sprintf("http://viacep.com.br/ws/%s/json/", cep)
+
+	var v ViaCEPResponse
+	if err := getJSON(ctx, url, &v); err != nil {
+		return Address{}, err
+	}
+	if v.Erro == true || v.Erro == "true" {
+		return Address{}, ErrNotFound
+	}
+	return v.address(), nil
+}
+
+// searchViaCEP usa a busca por endereço do ViaCEP, que devolve até 50 CEPs
+// cujo logradouro contém street na cidade e UF informadas. city e street
+// devem ter pelo menos 3 letras.
+func searchViaCEP(ctx context.Context, uf, city, street string) ([]Address, error) {
+	url := fmt.Sprintf("http://viacep.com.br/ws/%s/%s/%s/json/", url.PathEscape(uf), url.PathEscape(city), url.PathEscape(street))
+
+	var vs []ViaCEPResponse
+	if err := getJSON(ctx, url, &vs); err != nil {
+		return nil, err
+	}
+	addrs := make([]Address, len(vs))
+	for i, v := range vs {
+		addrs[i] = v.address()
+	}
+	return addrs, nil
+}
+
+func (v ViaCEPResponse) address() Address {
+	return Address{
+		CEP:          v.CEP,
+		Street:       v.Logradouro,
+		Complement:   v.Complemento,
+		Neighborhood: v.Bairro,
+		City:         v.Localidade,
+		State:        v.UF,
+		DDD:          v.DDD,
+		IBGE:         v.IBGE,
+		SIAFI:        v.SIAFI,
+		GIA:          v.GIA,
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type ZippopotamResponse struct {
+	PostCode            string `json:"post code"`
+	CountryAbbreviation string `json:"country abbreviation"`
+	Places              []struct {
+		PlaceName         string `json:"place name"`
+		State             string `json:"state"`
+		StateAbbreviation string `json:"state abbreviation"`
+		Latitude          string `json:"latitude"`
+		Longitude         string `json:"longitude"`
+	} `json:"places"`
+}
+
+// zippopotamProvider consulta códigos postais fora do Brasil. Não entra no
+// registro: é usado no lugar dos providers de CEP quando --country não é BR.
+type zippopotamProvider struct {
+	country string
+}
+
+func (zippopotamProvider) Name() string { return "Zippopotam" }
+
+func (p zippopotamProvider) Fetch(ctx context.Context, code string) (Address, error) {
+	u := fmt.Sprintf("https://api.zippopotam.us/%s/%s",
+		url.PathEscape(strings.ToLower(p.country)), url.PathEscape(code))
+
+	var z ZippopotamResponse
+	if err := getJSON(ctx, u, &z); err != nil {
+		return Address{}, err
+	}
+	if len(z.Places) == 0 {
+		return Address{}, ErrNotFound
+	}
+
+	place := z.Places[0]
+	state := place.StateAbbreviation
+	if state == "" {
+		state = place.State
+	}
+	addr := Address{
+		CEP:     z.PostCode,
+		City:    place.PlaceName,
+		State:   state,
+		Country: z.CountryAbbreviation,
+	}
+	lat, errLat := strconv.ParseFloat(place.Latitude, 64)
+	lng, errLng := strconv.ParseFloat(place.Longitude, 64)
+	if errLat == nil && errLng == nil {
+		addr.Location = &Location{Latitude: lat, Longitude: lng}
+	}
+	return addr, nil
+}
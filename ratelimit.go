@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimit configura o token bucket de um provider:
+//
+//	provider_settings:
+//	  ViaCEP:
+//	    rate_limit:
+//	      rate: 2   # requisições por segundo
+//	      burst: 5
+type RateLimit struct {
+	Rate  float64 `yaml:"rate"`
+	Burst int     `yaml:"burst"`
+}
+
+type tokenBucket struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	burst := float64(max(limit.Burst, 1))
+	return &tokenBucket{rate: limit.Rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// wait bloqueia até haver um token disponível ou ctx terminar.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		t := time.NewTimer(wait)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+type rateLimitProvider struct {
+	Provider
+	bucket *tokenBucket
+}
+
+// withRateLimit envolve p com um token bucket; sem limite configurado,
+// devolve p como está.
+func withRateLimit(p Provider, limit *RateLimit) Provider {
+	if limit == nil || limit.Rate <= 0 {
+		return p
+	}
+	return rateLimitProvider{Provider: p, bucket: newTokenBucket(*limit)}
+}
+
+func (r rateLimitProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	if err := r.bucket.wait(ctx); err != nil {
+		return Address{}, err
+	}
+	return r.Provider.Fetch(ctx, cep)
+}
+
+var errBackingOff error = localizedError("aguardando Retry-After")
+
+// backoffProvider respeita as respostas 429 do provider: até o fim do prazo
+// pedido em Retry-After, as consultas falham na hora, cedendo a corrida aos
+// demais providers sem gastar a cota.
+type backoffProvider struct {
+	Provider
+
+	mu    sync.Mutex
+	until time.Time
+}
+
+func withBackoff(p Provider) Provider {
+	return &backoffProvider{Provider: p}
+}
+
+func (b *backoffProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	b.mu.Lock()
+	until := b.until
+	b.mu.Unlock()
+	if wait := time.Until(until); wait > 0 {
+		return Address{}, fmt.Errorf("%w (%s)", errBackingOff, wait.Round(time.Second))
+	}
+
+	addr, err := b.Provider.Fetch(ctx, cep)
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) && statusErr.Code == http.StatusTooManyRequests {
+		rateLimitedTotal.inc(b.Name())
+		b.mu.Lock()
+		b.until = time.Now().Add(statusErr.RetryAfter)
+		b.mu.Unlock()
+	}
+	return addr, err
+}
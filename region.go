@@ -0,0 +1,22 @@
+package main
+
+import "strings"
+
+// stateRegions é a grande região (macrorregião) do IBGE de cada UF.
+var stateRegions = map[string]string{
+	"AC": "Norte", "AM": "Norte", "AP": "Norte", "PA": "Norte", "RO": "Norte", "RR": "Norte", "TO": "Norte",
+	"AL": "Nordeste", "BA": "Nordeste", "CE": "Nordeste", "MA": "Nordeste", "PB": "Nordeste",
+	"PE": "Nordeste", "PI": "Nordeste", "RN": "Nordeste", "SE": "Nordeste",
+	"DF": "Centro-Oeste", "GO": "Centro-Oeste", "MS": "Centro-Oeste", "MT": "Centro-Oeste",
+	"ES": "Sudeste", "MG": "Sudeste", "RJ": "Sudeste", "SP": "Sudeste",
+	"PR": "Sul", "RS": "Sul", "SC": "Sul",
+}
+
+// regionFor devolve a grande região de um endereço brasileiro pela UF, ou ""
+// fora do Brasil e com UF desconhecida.
+func regionFor(a Address) string {
+	if a.Country != "" && !strings.EqualFold(a.Country, "BR") {
+		return ""
+	}
+	return stateRegions[strings.ToUpper(a.State)]
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// runRepl lê CEPs do terminal, um por vez, reaproveitando os clientes HTTP e
+// o cache entre as consultas.
+func runRepl(args []string) int {
+	fs := flag.NewFlagSet("cep repl", flag.ExitOnError)
+	opts, format := registerReplFlags(fs)
+	parseFlags(fs, args)
+
+	write, ok := formats[*format]
+	if !ok {
+		fmt.Print(tr("formato desconhecido: %s (use %s)\n", *format, strings.Join(formatNames(), ", ")))
+		return exitUsage
+	}
+	app, err := opts.setup()
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	defer app.Close()
+
+	r := &repl{
+		app:     app,
+		write:   write,
+		all:     app.resolver.Providers,
+		enabled: map[string]bool{},
+		history: loadHistory(defaultHistoryPath()),
+	}
+	for _, p := range r.all {
+		r.enabled[p.Name()] = true
+	}
+	defer func() {
+		if err := saveHistory(defaultHistoryPath(), r.history); err != nil {
+			fmt.Fprint(os.Stderr, tr("Aviso: não foi possível salvar o histórico: %v\n", err))
+		}
+	}()
+
+	fmt.Println(tr("Digite um ou mais CEPs, ou :help para ver os comandos."))
+	r.loop(os.Stdin, os.Stdout)
+	return exitOK
+}
+
+func registerReplFlags(fs *flag.FlagSet) (*options, *string) {
+	return registerOptions(fs), fs.String("format", "text", "formato da saída: "+strings.Join(formatNames(), ", "))
+}
+
+type repl struct {
+	app   *app
+	write formatter
+	// all são os providers montados por setup; enabled diz quais deles
+	// estão em uso.
+	all     []Provider
+	enabled map[string]bool
+	history []string
+}
+
+// historySize é o máximo de linhas guardadas no histórico.
+const historySize = 500
+
+func (r *repl) loop(in io.Reader, out io.Writer) {
+	sc := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, "cep> ")
+		if !sc.Scan() {
+			fmt.Fprintln(out)
+			return
+		}
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+
+		// !! repete a última linha e !n, a n-ésima do histórico
+		if strings.HasPrefix(line, "!") {
+			prev, ok := r.recall(line[1:])
+			if !ok {
+				fmt.Fprint(out, tr("Histórico sem %s\n", line))
+				continue
+			}
+			line = prev
+			fmt.Fprintln(out, line)
+		}
+		r.history = append(r.history, line)
+		if len(r.history) > historySize {
+			r.history = r.history[len(r.history)-historySize:]
+		}
+
+		if strings.HasPrefix(line, ":") {
+			if !r.command(out, strings.Fields(line[1:])) {
+				return
+			}
+			continue
+		}
+		ceps := strings.Fields(line)
+		for i, lookup := range r.app.resolver.ResolveAll(context.Background(), ceps, 0) {
+			printLookup(out, out, r.app.resolver, ceps[i], lookup, r.write)
+		}
+	}
+}
+
+func (r *repl) recall(ref string) (string, bool) {
+	if len(r.history) == 0 {
+		return "", false
+	}
+	if ref == "!" {
+		return r.history[len(r.history)-1], true
+	}
+	n, err := strconv.Atoi(ref)
+	if err != nil || n < 1 || n > len(r.history) {
+		return "", false
+	}
+	return r.history[n-1], true
+}
+
+// command executa um comando do REPL; devolve false para encerrar.
+func (r *repl) command(out io.Writer, args []string) bool {
+	if len(args) == 0 {
+		args = []string{"help"}
+	}
+	switch args[0] {
+	case "q", "quit", "exit":
+		return false
+	case "history":
+		for i, line := range r.history {
+			fmt.Fprintf(out, "%4d  %s\n", i+1, line)
+		}
+	case "providers":
+		if len(args) > 1 {
+			if err := r.toggle(args[1:]); err != nil {
+				fmt.Fprintln(out, err)
+			}
+		}
+		for _, p := range r.all {
+			mark := " "
+			if r.enabled[p.Name()] {
+				mark = "x"
+			}
+			fmt.Fprintf(out, "[%s] %s\n", mark, p.Name())
+		}
+	case "help":
+		fmt.Fprintln(out, tr("<cep> [<cep>...]          consulta os CEPs"))
+		fmt.Fprintln(out, tr(":providers                lista os providers em uso"))
+		fmt.Fprintln(out, tr(":providers +Nome -Nome    liga ou desliga providers"))
+		fmt.Fprintln(out, tr(":providers Nome,Nome      usa só os providers informados"))
+		fmt.Fprintln(out, tr(":history                  mostra o histórico; !n repete a linha n e !! a última"))
+		fmt.Fprintln(out, tr(":quit                     sai"))
+	default:
+		fmt.Fprint(out, tr("Comando desconhecido: :%s (use :help)\n", args[0]))
+	}
+	return true
+}
+
+// toggle muda os providers em uso conforme args: +Nome liga, -Nome desliga e
+// uma lista sem sinal substitui a seleção.
+func (r *repl) toggle(args []string) error {
+	enabled := map[string]bool{}
+	for name, on := range r.enabled {
+		enabled[name] = on
+	}
+	for _, arg := range args {
+		var names []string
+		on, replace := true, false
+		switch arg[0] {
+		case '+':
+			names = strings.Split(arg[1:], ",")
+		case '-':
+			names, on = strings.Split(arg[1:], ","), false
+		default:
+			names, replace = strings.Split(arg, ","), true
+		}
+		if replace {
+			for name := range enabled {
+				enabled[name] = false
+			}
+		}
+		for _, name := range names {
+			p, ok := r.find(name)
+			if !ok {
+				return errors.New(tr("provider desconhecido: %s", name))
+			}
+			enabled[p.Name()] = on
+		}
+	}
+
+	var providers []Provider
+	for _, p := range r.all {
+		if enabled[p.Name()] {
+			providers = append(providers, p)
+		}
+	}
+	if len(providers) == 0 {
+		return errors.New(tr("ao menos um provider precisa ficar ligado"))
+	}
+	// Atualizações do cache em segundo plano leem a lista de providers
+	r.app.resolver.Wait()
+	r.app.resolver.Providers = providers
+	r.enabled = enabled
+	return nil
+}
+
+func (r *repl) find(name string) (Provider, bool) {
+	for _, p := range r.all {
+		if strings.EqualFold(p.Name(), strings.TrimSpace(name)) {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// defaultHistoryPath retorna ~/.cache/cep/history (ou equivalente do SO).
+func defaultHistoryPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "cep", "history")
+}
+
+func loadHistory(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+}
+
+func saveHistory(path string, history []string) error {
+	if path == "" || len(history) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.Join(history, "\n")+"\n"), 0o644)
+}
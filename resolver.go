@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Resolver executa a consulta de um CEP com os providers e a estratégia
+// configurados. É compartilhado por todas as consultas do processo, então
+// consultas simultâneas ao mesmo CEP viram uma única corrida (singleflight).
+type Resolver struct {
+	Providers []Provider
+	Strategy  Strategy
+	Options   StrategyOptions
+	// Timeout é o prazo de cada consulta.
+	Timeout time.Duration
+	// Cache, se definido, recebe os endereços resolvidos e é consultado
+	// quando todos os providers falham.
+	Cache Cache
+	// Country é o país dos códigos postais consultados (BR se vazio), que
+	// entra na chave do cache.
+	Country string
+	// CacheTTL é a validade das entradas gravadas em Cache.
+	CacheTTL time.Duration
+	// NegativeTTL é a validade, em geral menor, dos resultados "CEP não
+	// encontrado" gravados em Cache. Zero desliga o cache negativo.
+	NegativeTTL time.Duration
+	// Normalize, se definido, ajusta o CEP antes da consulta (por exemplo,
+	// removendo a formatação).
+	Normalize func(cep string) string
+	// Validate, se definido, recusa o CEP já normalizado antes de consultar
+	// o cache ou os providers.
+	Validate func(cep string) error
+	// SoftTTL, se definido, é a idade a partir da qual uma entrada ainda
+	// válida é devolvida na hora mas atualizada em segundo plano
+	// (stale-while-revalidate).
+	SoftTTL time.Duration
+	// Geocoders, se definidos, completam as coordenadas dos endereços
+	// resolvidos que vierem sem elas (veja enrich).
+	Geocoders []Geocoder
+	// Transforms reescrevem, em ordem, os endereços resolvidos (por exemplo,
+	// padronizando o tipo de logradouro).
+	Transforms []func(Address) Address
+
+	mu        sync.Mutex
+	flight    map[string]*call
+	refreshes sync.WaitGroup
+}
+
+// Lookup é o resultado de uma consulta.
+type Lookup struct {
+	Results []APIResult
+	// TimedOut indica que o prazo da consulta terminou antes da resposta.
+	TimedOut bool
+	// Warnings são as falhas que não impedem o resultado, como as da
+	// geocodificação.
+	Warnings []error
+}
+
+// OK informa se algum resultado da consulta é válido.
+func (l Lookup) OK() bool {
+	for _, res := range l.Results {
+		if res.Err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+type call struct {
+	done   chan struct{}
+	lookup Lookup
+}
+
+// Resolve consulta cep. Um endereço ainda válido no cache é devolvido sem
+// consultar a rede. Se já houver uma consulta ao mesmo CEP em andamento,
+// espera por ela e devolve o mesmo resultado em vez de iniciar outra.
+func (r *Resolver) Resolve(ctx context.Context, cep string) Lookup {
+	if r.Normalize != nil {
+		cep = r.Normalize(cep)
+	}
+	if r.Validate != nil {
+		if err := r.Validate(cep); err != nil {
+			return Lookup{Results: []APIResult{{Err: err}}}
+		}
+	}
+	return r.transform(r.geocode(r.cached(ctx, cep)))
+}
+
+// transform aplica Transforms aos resultados de lookup.
+func (r *Resolver) transform(lookup Lookup) Lookup {
+	if len(r.Transforms) == 0 {
+		return lookup
+	}
+	// Results pode ser compartilhado com outras consultas ao mesmo CEP
+	results := slices.Clone(lookup.Results)
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+		for _, t := range r.Transforms {
+			results[i].Addr = t(results[i].Addr)
+		}
+	}
+	lookup.Results = results
+	return lookup
+}
+
+// geocodeTimeout é o prazo da geocodificação de um resultado, separado do da
+// consulta.
+const geocodeTimeout = 2 * time.Second
+
+// geocode completa com Geocoders as coordenadas dos resultados de lookup.
+// As falhas vão para lookup.Warnings.
+func (r *Resolver) geocode(lookup Lookup) Lookup {
+	if len(r.Geocoders) == 0 || !lookup.OK() {
+		return lookup
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), geocodeTimeout)
+	defer cancel()
+	// Results pode ser compartilhado com outras consultas ao mesmo CEP
+	results := slices.Clone(lookup.Results)
+	for i, res := range results {
+		if res.Err != nil {
+			continue
+		}
+		var errs []error
+		results[i].Addr, errs = enrich(ctx, res.Addr, r.Geocoders)
+		lookup.Warnings = append(lookup.Warnings, errs...)
+	}
+	lookup.Results = results
+	return lookup
+}
+
+// cached devolve a entrada de cep no cache, se ainda válida, ou consulta os
+// providers.
+func (r *Resolver) cached(ctx context.Context, cep string) Lookup {
+	key := cacheKey(r.Country, cep)
+	if r.Cache != nil && key != "" {
+		if e, ok := r.Cache.Get(key); ok && !e.Expired() {
+			cacheLookupsTotal.inc("hit")
+			if r.SoftTTL > 0 && time.Since(e.StoredAt) > r.SoftTTL {
+				r.refresh(cep)
+			}
+			if e.NotFound {
+				err := fmt.Errorf("%w (cache)", ErrNotFound)
+				return Lookup{Results: []APIResult{{Source: e.Source, Err: err, CacheHit: true}}}
+			}
+			return Lookup{Results: []APIResult{{Addr: e.Addr, Source: e.Source, CacheHit: true}}}
+		}
+	}
+	if r.Cache != nil && key != "" {
+		cacheLookupsTotal.inc("miss")
+	}
+	return r.shared(ctx, cep)
+}
+
+// ResolveAll consulta os ceps simultaneamente, cada um com a sua própria
+// corrida entre os providers, e devolve os resultados na ordem de ceps. No
+// máximo concurrency consultas rodam ao mesmo tempo; zero não impõe limite.
+func (r *Resolver) ResolveAll(ctx context.Context, ceps []string, concurrency int) []Lookup {
+	lookups := make([]Lookup, len(ceps))
+	r.ResolveEach(ctx, ceps, concurrency, func(i int, l Lookup) { lookups[i] = l })
+	return lookups
+}
+
+// ResolveEach é como ResolveAll, mas chama fn com o índice e o resultado de
+// cada CEP assim que ele termina. fn é chamada de várias goroutines ao mesmo
+// tempo.
+func (r *Resolver) ResolveEach(ctx context.Context, ceps []string, concurrency int, fn func(i int, l Lookup)) {
+	if concurrency <= 0 {
+		concurrency = len(ceps)
+	}
+	sem := make(chan struct{}, max(concurrency, 1))
+	var wg sync.WaitGroup
+	for i, cep := range ceps {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i, r.Resolve(ctx, cep))
+		}()
+	}
+	wg.Wait()
+}
+
+// refresh atualiza cep no cache em segundo plano.
+func (r *Resolver) refresh(cep string) {
+	r.refreshes.Add(1)
+	go func() {
+		defer r.refreshes.Done()
+		r.shared(context.Background(), cep)
+	}()
+}
+
+// Wait espera as atualizações em segundo plano iniciadas por Resolve.
+func (r *Resolver) Wait() {
+	r.refreshes.Wait()
+}
+
+// shared faz a consulta de cep aos providers, compartilhando-a com quem
+// pedir o mesmo CEP enquanto ela estiver em andamento.
+func (r *Resolver) shared(ctx context.Context, cep string) Lookup {
+	r.mu.Lock()
+	if r.flight == nil {
+		r.flight = map[string]*call{}
+	}
+	if c, ok := r.flight[cep]; ok {
+		r.mu.Unlock()
+		select {
+		case <-c.done:
+			return c.lookup
+		case <-ctx.Done():
+			return Lookup{Results: []APIResult{{Err: ctxError(ctx)}}, TimedOut: errors.Is(ctx.Err(), context.DeadlineExceeded)}
+		}
+	}
+	c := &call{done: make(chan struct{})}
+	r.flight[cep] = c
+	r.mu.Unlock()
+
+	// A consulta não é cancelada se quem a iniciou desistir, já que outros
+	// podem estar esperando por ela
+	c.lookup = r.resolve(context.WithoutCancel(ctx), cep)
+	close(c.done)
+
+	r.mu.Lock()
+	delete(r.flight, cep)
+	r.mu.Unlock()
+	return c.lookup
+}
+
+func (r *Resolver) resolve(ctx context.Context, cep string) Lookup {
+	ctx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	results := r.Strategy(ctx, cep, r.Providers, r.Options)
+	lookup := Lookup{Results: results, TimedOut: errors.Is(ctx.Err(), context.DeadlineExceeded)}
+	if lookup.OK() && len(results) == 1 {
+		raceWinsTotal.inc(results[0].Source)
+	}
+	key := cacheKey(r.Country, cep)
+	if r.Cache == nil || key == "" {
+		return lookup
+	}
+
+	if lookup.OK() {
+		// Só guarda consultas que resultam num único endereço; a estratégia
+		// all, por exemplo, devolve um por provider
+		if len(results) == 1 {
+			r.store(key, results[0])
+		}
+		return lookup
+	}
+
+	if len(results) == 1 && errors.Is(results[0].Err, ErrNotFound) {
+		if r.NegativeTTL > 0 {
+			now := time.Now()
+			r.Cache.Set(key, CacheEntry{StoredAt: now, ExpiresAt: now.Add(r.NegativeTTL), NotFound: true}, r.NegativeTTL)
+		}
+		return lookup
+	}
+
+	// Todos falharam: um endereço desatualizado é melhor que um erro
+	if e, ok := r.Cache.Get(key); ok && !e.NotFound {
+		cacheLookupsTotal.inc("stale")
+		return Lookup{Results: []APIResult{{Addr: e.Addr, Source: e.Source, CacheHit: true, Stale: e.Expired()}}}
+	}
+	return lookup
+}
+
+// store grava res no cache, na chave key.
+func (r *Resolver) store(key string, res APIResult) {
+	now := time.Now()
+	e := CacheEntry{Addr: res.Addr, Source: res.Source, StoredAt: now}
+	if r.CacheTTL > 0 {
+		e.ExpiresAt = now.Add(r.CacheTTL)
+	}
+	r.Cache.Set(key, e, r.CacheTTL)
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestResolverCountsRaceWinsWithoutCache(t *testing.T) {
+	r := &Resolver{
+		Providers: []Provider{stubProvider{name: "SemCache", addr: Address{CEP: "01001000"}}},
+		Strategy:  raceStrategy,
+		Timeout:   time.Second,
+	}
+	before := raceWinsTotal.snapshot()["SemCache"]
+	r.Resolve(context.Background(), "01001000")
+	if got := raceWinsTotal.snapshot()["SemCache"]; got != before+1 {
+		t.Errorf("vitórias de SemCache = %d, quero %d", got, before+1)
+	}
+}
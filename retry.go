@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy define como um provider é consultado de novo após uma falha
+// transitória de rede ou uma resposta 5xx. No arquivo de configuração:
+//
+//	provider_settings:
+//	  ViaCEP:
+//	    retry:
+//	      count: 2
+//	      base_delay: 100ms
+//	      jitter: 0.2
+type RetryPolicy struct {
+	// Count é o número de novas tentativas após a primeira.
+	Count int `yaml:"count"`
+	// BaseDelay é a espera antes da primeira nova tentativa; dobra a cada
+	// tentativa seguinte.
+	BaseDelay time.Duration `yaml:"base_delay"`
+	// Jitter é a variação aleatória aplicada à espera, como fração dela
+	// (0.2 = ±20%).
+	Jitter float64 `yaml:"jitter"`
+}
+
+// delay devolve a espera antes da nova tentativa de número attempt (0 é a
+// primeira nova tentativa).
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.BaseDelay << attempt
+	if p.Jitter > 0 {
+		d += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(d))
+	}
+	return max(d, 0)
+}
+
+// retryBudget limita o total de novas tentativas de todos os providers no
+// processo, para que um provider fora do ar não multiplique a duração de uma
+// execução com muitos CEPs. Um orçamento nil é ilimitado.
+type retryBudget struct {
+	remaining atomic.Int64
+}
+
+func newRetryBudget(n int) *retryBudget {
+	if n <= 0 {
+		return nil
+	}
+	b := &retryBudget{}
+	b.remaining.Store(int64(n))
+	return b
+}
+
+// take consome uma tentativa do orçamento, se ainda houver.
+func (b *retryBudget) take() bool {
+	if b == nil {
+		return true
+	}
+	return b.remaining.Add(-1) >= 0
+}
+
+type retryProvider struct {
+	Provider
+	policy RetryPolicy
+	budget *retryBudget
+}
+
+// withRetry envolve p para repetir falhas transitórias segundo policy,
+// consumindo budget a cada nova tentativa.
+func withRetry(p Provider, policy RetryPolicy, budget *retryBudget) Provider {
+	if policy.Count <= 0 {
+		return p
+	}
+	return retryProvider{Provider: p, policy: policy, budget: budget}
+}
+
+func (r retryProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	for attempt := 0; ; attempt++ {
+		addr, err := r.Provider.Fetch(ctx, cep)
+		if err == nil || attempt >= r.policy.Count || ctx.Err() != nil || !isTransient(err) {
+			return addr, err
+		}
+
+		// Só tenta de novo se a espera couber no prazo que resta
+		wait := r.policy.delay(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= wait {
+			return addr, err
+		}
+		if !r.budget.take() {
+			return addr, err
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return addr, err
+		}
+	}
+}
+
+// isTransient informa se err é uma falha de rede que pode não se repetir.
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code >= 500
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary || dnsErr.IsTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
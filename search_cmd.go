@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// searchFlags são as opções do subcomando search.
+type searchFlags struct {
+	lookupFlags
+	uf, city, street string
+	page, perPage    int
+}
+
+func registerSearchFlags(fs *flag.FlagSet) *searchFlags {
+	f := &searchFlags{lookupFlags: lookupFlags{opts: registerOptions(fs)}}
+	f.registerOutputFlags(fs)
+	fs.StringVar(&f.uf, "uf", "", "UF do endereço, ex.: SP")
+	fs.StringVar(&f.city, "city", "", "cidade do endereço (pelo menos 3 letras)")
+	fs.StringVar(&f.street, "street", "", "logradouro, ou parte dele (pelo menos 3 letras)")
+	fs.IntVar(&f.page, "page", 1, "página dos resultados a mostrar")
+	fs.IntVar(&f.perPage, "per-page", 10, "resultados por página; 0 mostra todos")
+	return f
+}
+
+// runSearch procura os CEPs de um logradouro com a busca por endereço do
+// ViaCEP e mostra uma página dos resultados. O endereço pode vir em texto
+// livre, como argumento; os candidatos saem então do mais para o menos
+// provável.
+func runSearch(args []string) int {
+	fs := flag.NewFlagSet("cep search", flag.ExitOnError)
+	f := registerSearchFlags(fs)
+	parseFlags(fs, args)
+	f.formatSet = flagSet(fs, "format")
+
+	var parsed *parsedAddress
+	if fs.NArg() == 1 && f.uf == "" && f.city == "" && f.street == "" {
+		a, err := parseAddress(fs.Arg(0))
+		if err != nil {
+			fmt.Println(err)
+			return exitUsage
+		}
+		parsed, f.scored = &a, true
+		f.uf, f.city, f.street = a.State, a.City, a.Street
+		// O ViaCEP procura pelas palavras do nome; o tipo abreviado ("Av.")
+		// atrapalharia, e a ordenação já o leva em conta
+		if _, rest, ok := findStreetType(a.Street); ok && utf8.RuneCountInString(rest) >= 3 {
+			f.street = rest
+		}
+		if !f.quiet {
+			fmt.Fprint(os.Stderr, tr("Buscando %s\n", describeParsed(a)))
+		}
+	}
+	if (fs.NArg() > 0 && parsed == nil) || f.uf == "" || f.city == "" || f.street == "" {
+		fmt.Println(tr("Uso: go run main.go search [opções] --uf SP --city \"São Paulo\" --street Paulista"))
+		fmt.Println(tr("  ou: go run main.go search [opções] \"Av. Paulista 1578, Bela Vista, São Paulo SP\""))
+		return exitUsage
+	}
+	if err := f.check(); err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	if !f.formatSet && !f.oneline && f.template == "" && !f.quiet {
+		f.format = "table"
+	}
+	write, err := f.formatter()
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+
+	app, err := f.opts.setup()
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	defer app.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), app.resolver.Timeout)
+	addrs, err := searchViaCEP(ctx, strings.ToUpper(f.uf), f.city, f.street)
+	cancel()
+	errw := os.Stdout
+	if f.quiet {
+		errw = os.Stderr
+	}
+	if err != nil {
+		fmt.Fprintln(errw, paint(errw, colorError, tr("Erro na busca: %v", err)))
+		return exitCode(err)
+	}
+	if len(addrs) == 0 {
+		fmt.Fprintln(errw, paint(errw, colorError, tr("Nenhum endereço encontrado")))
+		return exitNotFound
+	}
+
+	var results []APIResult
+	if parsed != nil {
+		results = rankCandidates(*parsed, addrs)
+	} else {
+		for _, a := range addrs {
+			results = append(results, APIResult{Addr: a, Source: "ViaCEP"})
+		}
+	}
+	pages := 1
+	if f.perPage > 0 {
+		pages = (len(results) + f.perPage - 1) / f.perPage
+		if f.page > pages {
+			fmt.Println(tr("página %d não existe: são %d páginas", f.page, pages))
+			return exitUsage
+		}
+		results = results[(f.page-1)*f.perPage : min(f.page*f.perPage, len(results))]
+	}
+
+	lookup := app.resolver.transform(app.resolver.geocode(Lookup{Results: results}))
+	for _, err := range lookup.Warnings {
+		fmt.Fprint(os.Stderr, tr("Aviso: geocodificação falhou: %v\n", err))
+	}
+	for i, res := range lookup.Results {
+		if i > 0 && !f.quiet && !f.oneLine() {
+			fmt.Println()
+		}
+		if err := write(os.Stdout, res); err != nil {
+			fmt.Fprint(os.Stderr, tr("Erro ao escrever a saída: %v\n", err))
+		}
+	}
+	if f.flush != nil {
+		f.flush()
+	}
+	if pages > 1 && !f.quiet {
+		fmt.Fprint(os.Stderr, tr("Página %d de %d; use --page para ver as outras\n", f.page, pages))
+	}
+	return exitOK
+}
+
+// check confere as opções antes da busca, com as mesmas regras do ViaCEP.
+func (f *searchFlags) check() error {
+	if !strings.EqualFold(f.opts.country, "BR") {
+		return errors.New(tr("a busca por endereço só está disponível para o Brasil"))
+	}
+	if _, ok := stateNames[strings.ToUpper(f.uf)]; !ok {
+		return errors.New(tr("UF desconhecida: %s", f.uf))
+	}
+	if utf8.RuneCountInString(strings.TrimSpace(f.city)) < 3 {
+		return errors.New(tr("--city deve ter pelo menos 3 letras"))
+	}
+	if utf8.RuneCountInString(strings.TrimSpace(f.street)) < 3 {
+		return errors.New(tr("--street deve ter pelo menos 3 letras"))
+	}
+	if f.page < 1 || f.perPage < 0 {
+		return errors.New(tr("--page deve ser pelo menos 1 e --per-page não pode ser negativo"))
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// serveFlags são as opções do subcomando serve.
+type serveFlags struct {
+	opts        *options
+	addr        string
+	readyWindow time.Duration
+	probeCEP    string
+	grace       time.Duration
+	metricsFile string
+	corsOrigins string
+	corsMethods string
+	corsHeaders string
+	apiKeysFile string
+}
+
+func registerServeFlags(fs *flag.FlagSet) *serveFlags {
+	f := &serveFlags{opts: registerOptions(fs)}
+	fs.StringVar(&f.addr, "addr", "localhost:8080", "endereço em que o servidor escuta, ex.: :8080 para todas as interfaces")
+	fs.DurationVar(&f.readyWindow, "ready-window", 5*time.Minute, "o /readyz exige que algum provider tenha respondido neste intervalo")
+	fs.StringVar(&f.probeCEP, "probe-cep", "01001000", "CEP consultado pelo /readyz quando nenhum provider respondeu recentemente")
+	fs.DurationVar(&f.grace, "grace-period", 10*time.Second, "ao receber SIGTERM ou SIGINT, prazo para terminar as consultas em andamento")
+	fs.StringVar(&f.corsOrigins, "cors-origins", "", "origens que podem chamar a API pelo navegador, separadas por vírgula (ex.: https://loja.com.br,https://*.loja.com.br ou *); vazio desliga o CORS")
+	fs.StringVar(&f.corsMethods, "cors-methods", "GET,OPTIONS", "métodos permitidos nas chamadas CORS, separados por vírgula")
+	fs.StringVar(&f.apiKeysFile, "api-keys-file", "", "arquivo com as chaves de API aceitas, uma \"nome:chave\" por linha; soma-se a server.api_keys do config.yaml")
+	fs.StringVar(&f.corsHeaders, "cors-headers", "Content-Type,Authorization,X-API-Key", "cabeçalhos permitidos nas chamadas CORS, separados por vírgula")
+	fs.StringVar(&f.metricsFile, "metrics-file", "", "ao sair, grava as métricas finais neste arquivo, no formato do Prometheus")
+	return f
+}
+
+// runServe expõe a consulta como uma API HTTP: GET /cep/{cep} devolve o
+// endereço em JSON, e /healthz e /readyz servem às sondas do Kubernetes. Com
+// SIGTERM ou SIGINT, para de aceitar conexões, espera as consultas em
+// andamento por até --grace-period e grava o cache antes de sair. Com chaves
+// de API configuradas, todas as rotas menos as sondas exigem uma delas.
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("cep serve", flag.ExitOnError)
+	f := registerServeFlags(fs)
+	parseFlags(fs, args)
+
+	if fs.NArg() > 0 {
+		fmt.Println(tr("Uso: go run main.go serve [opções] [--addr localhost:8080]"))
+		return exitUsage
+	}
+	app, err := f.opts.setup()
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	defer app.Close()
+
+	s := newServer(app, os.Stderr)
+	s.readyWindow, s.probeCEP = f.readyWindow, normalizeCEP(f.probeCEP)
+	s.cors = corsPolicy{origins: splitList(f.corsOrigins), methods: splitList(f.corsMethods), headers: splitList(f.corsHeaders)}
+	if s.auth, err = loadAPIKeys(app.cfg.Server.APIKeys, f.apiKeysFile); err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	if !s.auth.enabled() && !loopbackAddr(f.addr) {
+		fmt.Fprint(os.Stderr, tr("Aviso: a API está aberta a outras máquinas sem chave de API; veja --api-keys-file\n"))
+	}
+	srv := &http.Server{Addr: f.addr, Handler: s.handler(), ReadHeaderTimeout: 5 * time.Second}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	fmt.Fprint(os.Stderr, tr("Servindo em http://%s\n", f.addr))
+
+	select {
+	case err := <-errc:
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	case <-ctx.Done():
+	}
+	stop()
+	fmt.Fprint(os.Stderr, tr("Encerrando: esperando as consultas em andamento por até %s\n", f.grace))
+	shutdown, cancel := context.WithTimeout(context.Background(), f.grace)
+	defer cancel()
+	code := exitOK
+	if err := srv.Shutdown(shutdown); err != nil {
+		fmt.Fprint(os.Stderr, tr("Prazo de encerramento esgotado; conexões abertas foram fechadas: %v\n", err))
+		code = exitTimeout
+	}
+	if f.metricsFile != "" {
+		if err := writeMetricsFile(f.metricsFile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+	// O defer de app.Close espera as atualizações em segundo plano e grava o
+	// cache
+	return code
+}
+
+// writeMetricsFile grava as métricas em path, trocando o arquivo de uma vez
+// para que um coletor nunca leia um arquivo pela metade.
+func writeMetricsFile(path string) error {
+	var buf bytes.Buffer
+	writeMetrics(&buf)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// server é a API HTTP do subcomando serve. Cada requisição faz a mesma
+// consulta da CLI, com o Resolver compartilhado (e, portanto, o mesmo cache
+// e o singleflight).
+type server struct {
+	app *app
+	// log recebe uma linha por requisição atendida.
+	log io.Writer
+
+	health *providerHealth
+	// readyWindow é quão recente deve ser a resposta de algum provider para
+	// o /readyz responder que o servidor está pronto.
+	readyWindow time.Duration
+	// probeCEP é o CEP consultado pelo /readyz quando não há respostas
+	// recentes.
+	probeCEP string
+	cors     corsPolicy
+	auth     apiKeyAuth
+}
+
+// newServer cria o servidor sobre app, acompanhando as respostas dos
+// providers para o /readyz e o /metrics.
+func newServer(app *app, log io.Writer) *server {
+	s := &server{app: app, log: log, health: &providerHealth{}, readyWindow: 5 * time.Minute, probeCEP: "01001000"}
+	for i, p := range app.resolver.Providers {
+		app.resolver.Providers[i] = instrumentedProvider{Provider: p, health: s.health}
+	}
+	return s
+}
+
+// route é um endpoint do servidor. A mesma lista registra os handlers e
+// gera o documento OpenAPI, para que os dois não se desencontrem.
+type route struct {
+	method, path string
+	summary      string
+	handle       http.HandlerFunc
+	responses    []routeResponse
+	// public dispensa a chave de API, como nas sondas do Kubernetes.
+	public bool
+	// auth é preenchido por routes quando a rota exige a chave de API.
+	auth bool
+}
+
+// routeResponse é uma resposta possível de um route. body é um valor do
+// tipo do corpo (ou nil, sem corpo), de onde o esquema é derivado.
+type routeResponse struct {
+	status      int
+	description string
+	contentType string
+	body        any
+}
+
+func jsonResponse(status int, description string, body any) routeResponse {
+	return routeResponse{status: status, description: description, contentType: "application/json", body: body}
+}
+
+// routes lista os endpoints do servidor.
+func (s *server) routes() []route {
+	errorBody := errorResponse{}
+	routes := []route{
+		{method: "GET", path: "/cep/{cep}", summary: "Consulta um CEP com a corrida entre os providers", handle: s.handleCEP, responses: []routeResponse{
+			jsonResponse(http.StatusOK, "Endereço encontrado", jsonResult{}),
+			jsonResponse(http.StatusBadRequest, "CEP inválido", errorBody),
+			jsonResponse(http.StatusNotFound, "CEP não encontrado", errorBody),
+			jsonResponse(http.StatusBadGateway, "Todos os providers falharam", errorBody),
+			jsonResponse(http.StatusGatewayTimeout, "Tempo esgotado", errorBody),
+		}},
+		{method: "GET", path: "/healthz", summary: "O processo está de pé (liveness)", handle: s.handleHealthz, responses: []routeResponse{
+			jsonResponse(http.StatusOK, "No ar", healthResponse{}),
+		}, public: true},
+		{method: "GET", path: "/readyz", summary: "Algum provider respondeu recentemente (readiness)", handle: s.handleReadyz, responses: []routeResponse{
+			jsonResponse(http.StatusOK, "Pronto", readyResponse{}),
+			jsonResponse(http.StatusServiceUnavailable, "Nenhum provider respondeu", readyResponse{}),
+		}, public: true},
+		{method: "GET", path: "/metrics", summary: "Métricas no formato de texto do Prometheus", handle: s.handleMetrics, responses: []routeResponse{
+			{status: http.StatusOK, description: "Métricas", contentType: "text/plain", body: ""},
+		}},
+		{method: "GET", path: "/openapi.json", summary: "Este documento OpenAPI", handle: s.handleOpenAPI, responses: []routeResponse{
+			{status: http.StatusOK, description: "Documento OpenAPI 3", contentType: "application/json"},
+		}},
+	}
+	if s.auth.enabled() {
+		for i := range routes {
+			if !routes[i].public {
+				routes[i].auth = true
+				routes[i].responses = append(routes[i].responses, jsonResponse(http.StatusUnauthorized, "Chave de API ausente ou inválida", errorBody))
+			}
+		}
+	}
+	return routes
+}
+
+// handler monta as rotas do servidor.
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	for _, r := range s.routes() {
+		handle := r.handle
+		if r.auth {
+			handle = s.auth.wrap(handle)
+		}
+		mux.HandleFunc(r.method+" "+r.path, handle)
+	}
+	return s.logRequests(s.cors.wrap(mux))
+}
+
+// handleCEP responde com o endereço no formato json da CLI ou, em caso de
+// falha, com {"error": ...} e o status correspondente à causa.
+func (s *server) handleCEP(w http.ResponseWriter, r *http.Request) {
+	cep := r.PathValue("cep")
+	lookup := s.app.resolver.Resolve(r.Context(), cep)
+	for _, res := range lookup.Results {
+		if res.Err == nil {
+			writeJSONResponse(w, http.StatusOK, newJSONResult(res))
+			return
+		}
+	}
+	writeJSONError(w, lookupStatus(lookup), lookupError(lookup))
+}
+
+func (s *server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writeMetrics(w)
+}
+
+// lookupStatus é o status HTTP de uma consulta que falhou, equivalente ao
+// código de saída da CLI.
+func lookupStatus(l Lookup) int {
+	switch lookupExitCode(l) {
+	case exitInvalidCEP:
+		return http.StatusBadRequest
+	case exitNotFound:
+		return http.StatusNotFound
+	case exitTimeout:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// lookupError junta as falhas de uma consulta num único erro.
+func lookupError(l Lookup) error {
+	if l.TimedOut {
+		return ErrTimeout
+	}
+	if len(l.Results) == 1 {
+		return l.Results[0].Err
+	}
+	var errs []error
+	for _, res := range l.Results {
+		errs = append(errs, res.Err)
+	}
+	return allFailed(errs)
+}
+
+func writeJSONResponse(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	if err == nil {
+		err = errors.New(http.StatusText(status))
+	}
+	writeJSONResponse(w, status, errorResponse{Error: err.Error()})
+}
+
+// statusRecorder guarda o status escrito pelo handler, para o log.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	// client é o nome da chave de API usada, se houver.
+	client string
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// logRequests escreve em s.log o método, o caminho, o status e a duração de
+// cada requisição e, com chaves de API, o nome da chave usada ("-" sem
+// chave válida).
+func (s *server) logRequests(next http.Handler) http.Handler {
+	var mu sync.Mutex
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		httpRequestsTotal.inc(strconv.Itoa(rec.status))
+		mu.Lock()
+		defer mu.Unlock()
+		line := fmt.Sprintf("%s %s %s %d %s", start.Format(time.RFC3339), r.Method, r.URL.Path, rec.status, time.Since(start).Round(time.Millisecond))
+		if s.auth.enabled() {
+			line += " " + cmp.Or(rec.client, "-")
+		}
+		fmt.Fprintln(s.log, line)
+	})
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+// apiKey é uma chave aceita pelo servidor. Só o hash fica em memória, e é
+// ele que se compara, para que o tempo da comparação não dependa do tamanho
+// da chave.
+type apiKey struct {
+	name string
+	hash [sha256.Size]byte
+}
+
+// apiKeyAuth exige uma das chaves em X-API-Key ou em "Authorization: Bearer".
+// Sem chaves, a autenticação fica desligada.
+type apiKeyAuth struct {
+	keys []apiKey
+}
+
+func (a apiKeyAuth) enabled() bool { return len(a.keys) > 0 }
+
+// loadAPIKeys junta as chaves do config.yaml (server.api_keys, pelo nome de
+// quem as usa) às do arquivo path, com uma chave "nome:chave" por linha.
+func loadAPIKeys(fromConfig map[string]string, path string) (apiKeyAuth, error) {
+	named := map[string]string{}
+	for name, key := range fromConfig {
+		named[name] = key
+	}
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return apiKeyAuth{}, err
+		}
+		defer f.Close()
+		sc := bufio.NewScanner(f)
+		for n := 1; sc.Scan(); n++ {
+			line := strings.TrimSpace(sc.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			name, key, ok := strings.Cut(line, ":")
+			if !ok {
+				return apiKeyAuth{}, fmt.Errorf("%s:%d: %s", path, n, tr("esperava nome:chave"))
+			}
+			named[strings.TrimSpace(name)] = strings.TrimSpace(key)
+		}
+		if err := sc.Err(); err != nil {
+			return apiKeyAuth{}, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var a apiKeyAuth
+	seen := map[[sha256.Size]byte]string{}
+	for _, name := range names {
+		key := named[name]
+		if name == "" || key == "" {
+			return apiKeyAuth{}, errors.New(tr("chave de API sem nome ou vazia"))
+		}
+		h := sha256.Sum256([]byte(key))
+		if other, dup := seen[h]; dup {
+			return apiKeyAuth{}, errors.New(tr("as chaves de API de %s e %s são iguais; cada cliente precisa da sua", other, name))
+		}
+		seen[h] = name
+		a.keys = append(a.keys, apiKey{name: name, hash: h})
+	}
+	return a, nil
+}
+
+// identify devolve o nome da chave enviada em r, se ela for válida. Todas as
+// chaves são comparadas, mesmo depois de achar a certa.
+func (a apiKeyAuth) identify(r *http.Request) (string, bool) {
+	key := r.Header.Get("X-API-Key")
+	if key == "" {
+		if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+			key = strings.TrimSpace(token)
+		}
+	}
+	if key == "" {
+		return "", false
+	}
+	h := sha256.Sum256([]byte(key))
+	name := ""
+	for _, k := range a.keys {
+		if subtle.ConstantTimeCompare(h[:], k.hash[:]) == 1 {
+			name = k.name
+		}
+	}
+	return name, name != ""
+}
+
+// wrap recusa com 401 as requisições sem uma chave válida. O nome da chave vai
+// para o log de acesso, pelo statusRecorder de logRequests.
+func (a apiKeyAuth) wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name, ok := a.identify(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="cep"`)
+			writeJSONError(w, http.StatusUnauthorized, errors.New(tr("chave de API ausente ou inválida")))
+			return
+		}
+		if rec, ok := w.(*statusRecorder); ok {
+			rec.client = name
+		}
+		next(w, r)
+	}
+}
+
+// loopbackAddr informa se addr só aceita conexões da própria máquina.
+func loopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
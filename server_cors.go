@@ -0,0 +1,81 @@
+package main
+
+import (
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// corsMaxAge é por quantos segundos o navegador pode guardar a resposta do
+// preflight.
+const corsMaxAge = 600
+
+// corsPolicy diz quais origens (páginas de outros domínios) podem chamar a
+// API pelo navegador. Sem origens, o CORS fica desligado.
+type corsPolicy struct {
+	// origins aceita "*" (qualquer origem) e curingas de path.Match, como
+	// "https://*.loja.com.br".
+	origins []string
+	methods []string
+	headers []string
+}
+
+// allowed informa se a origem origin pode chamar a API.
+func (c corsPolicy) allowed(origin string) bool {
+	for _, o := range c.origins {
+		if o == "*" || strings.EqualFold(o, origin) {
+			return true
+		}
+		if ok, _ := path.Match(o, origin); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// wrap acrescenta às respostas os cabeçalhos CORS e responde sozinho aos
+// preflights (OPTIONS com Access-Control-Request-Method).
+func (c corsPolicy) wrap(next http.Handler) http.Handler {
+	if len(c.origins) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		w.Header().Add("Vary", "Origin")
+		if origin == "" || !c.allowed(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if slices.Contains(c.origins, "*") {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+
+		method := r.Header.Get("Access-Control-Request-Method")
+		if r.Method != http.MethodOptions || method == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if slices.ContainsFunc(c.methods, func(m string) bool { return strings.EqualFold(m, method) }) {
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(c.methods, ", "))
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(c.headers, ", "))
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(corsMaxAge))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// splitList separa uma lista de itens separados por vírgula, ignorando os
+// vazios.
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// probeTimeout é o prazo da consulta de teste do /readyz.
+const probeTimeout = 2 * time.Second
+
+// providerHealth guarda quando cada provider respondeu pela última vez. "CEP
+// não encontrado" também conta: o provider está no ar.
+type providerHealth struct {
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+func (h *providerHealth) record(name string, err error) {
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.last == nil {
+		h.last = map[string]time.Time{}
+	}
+	h.last[name] = time.Now()
+}
+
+// since devolve os providers que responderam depois de t, com o horário da
+// última resposta.
+func (h *providerHealth) since(t time.Time) map[string]time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := map[string]time.Time{}
+	for name, at := range h.last {
+		if at.After(t) {
+			out[name] = at
+		}
+	}
+	return out
+}
+
+// instrumentedProvider registra em health as respostas de Provider e conta
+// as consultas, as falhas e a latência de cada uma para o /metrics.
+type instrumentedProvider struct {
+	Provider
+	health *providerHealth
+}
+
+func (p instrumentedProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	start := time.Now()
+	addr, err := p.Provider.Fetch(ctx, cep)
+	name := p.Name()
+	providerRequestsTotal.inc(name)
+	// As consultas canceladas porque outro provider venceu não são falhas
+	if err != nil && !errors.Is(err, ErrNotFound) && !errors.Is(err, context.Canceled) {
+		providerErrorsTotal.inc(name)
+	}
+	if err == nil || errors.Is(err, ErrNotFound) {
+		providerLatencySeconds.observe(name, time.Since(start))
+	}
+	p.health.record(name, err)
+	return addr, err
+}
+
+type healthResponse struct {
+	Status string `json:"status"`
+}
+
+// handleHealthz só confirma que o processo está de pé (liveness).
+func (s *server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeJSONResponse(w, http.StatusOK, healthResponse{Status: "ok"})
+}
+
+type readyResponse struct {
+	Status    string               `json:"status"`
+	Providers map[string]time.Time `json:"providers"`
+}
+
+// handleReadyz responde 200 se algum provider respondeu nos últimos
+// s.readyWindow (readiness). Sem respostas recentes, por exemplo logo ao
+// subir, consulta s.probeCEP em todos os providers antes de decidir.
+func (s *server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	recent := s.health.since(time.Now().Add(-s.readyWindow))
+	if len(recent) == 0 {
+		s.probe(r.Context())
+		recent = s.health.since(time.Now().Add(-s.readyWindow))
+	}
+	if len(recent) == 0 {
+		writeJSONResponse(w, http.StatusServiceUnavailable, readyResponse{Status: "unavailable", Providers: recent})
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, readyResponse{Status: "ready", Providers: recent})
+}
+
+// probe consulta s.probeCEP em todos os providers ao mesmo tempo, sem
+// passar pelo cache; as respostas ficam em s.health.
+func (s *server) probe(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	for _, p := range s.app.resolver.Providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Fetch(ctx, s.probeCEP)
+		}()
+	}
+	wg.Wait()
+}
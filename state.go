@@ -0,0 +1,23 @@
+package main
+
+import "strings"
+
+// stateNames é o nome por extenso de cada UF.
+var stateNames = map[string]string{
+	"AC": "Acre", "AL": "Alagoas", "AM": "Amazonas", "AP": "Amapá", "BA": "Bahia",
+	"CE": "Ceará", "DF": "Distrito Federal", "ES": "Espírito Santo", "GO": "Goiás",
+	"MA": "Maranhão", "MG": "Minas Gerais", "MS": "Mato Grosso do Sul", "MT": "Mato Grosso",
+	"PA": "Pará", "PB": "Paraíba", "PE": "Pernambuco", "PI": "Piauí", "PR": "Paraná",
+	"RJ": "Rio de Janeiro", "RN": "Rio Grande do Norte", "RO": "Rondônia", "RR": "Roraima",
+	"RS": "Rio Grande do Sul", "SC": "Santa Catarina", "SE": "Sergipe", "SP": "São Paulo",
+	"TO": "Tocantins",
+}
+
+// stateNameFor devolve o nome do estado de um endereço brasileiro pela UF,
+// ou "" fora do Brasil e com UF desconhecida.
+func stateNameFor(a Address) string {
+	if a.Country != "" && !strings.EqualFold(a.Country, "BR") {
+		return ""
+	}
+	return stateNames[strings.ToUpper(a.State)]
+}
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Strategy decide como os providers são consultados e devolve os resultados
+// que devem ser exibidos, em ordem.
+type Strategy func(ctx context.Context, cep string, providers []Provider, opts StrategyOptions) []APIResult
+
+// StrategyOptions reúne os ajustes das estratégias vindos da linha de comando.
+type StrategyOptions struct {
+	// HedgeDelay é quanto a estratégia hedge espera antes de acionar o
+	// próximo provider.
+	HedgeDelay time.Duration
+	// Weights é o peso do voto de cada provider no quórum; ausentes valem 1.
+	Weights map[string]int
+	// Quorum é o mínimo de votos concordantes exigido; zero exige maioria.
+	Quorum int
+	// Prefer é o provider cuja resposta a estratégia prefer aguarda por até
+	// PreferGrace antes de aceitar o vencedor da corrida.
+	Prefer      string
+	PreferGrace time.Duration
+	// StepTimeout limita cada tentativa da estratégia fallback. Sem ele, o
+	// prazo restante é dividido igualmente entre os providers que faltam.
+	StepTimeout time.Duration
+}
+
+// weight devolve o peso do provider name em opts.
+func (opts StrategyOptions) weight(name string) int {
+	if w, ok := opts.Weights[name]; ok {
+		return w
+	}
+	return 1
+}
+
+var strategies = map[string]Strategy{
+	"race":     raceStrategy,
+	"all":      allStrategy,
+	"fallback": fallbackStrategy,
+	"quorum":   quorumStrategy,
+	"merge":    mergeStrategy,
+	"hedge":    hedgeStrategy,
+	"prefer":   preferStrategy,
+}
+
+// strategyNames lista as estratégias disponíveis, para mensagens de ajuda.
+func strategyNames() []string {
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// launch dispara todos os providers em paralelo, enviando em ch cada
+// resultado junto com o índice do provider.
+func launch(ctx context.Context, cep string, providers []Provider, ch chan<- indexedResult) {
+	for i, p := range providers {
+		go func(i int, p Provider) {
+			start := time.Now()
+			addr, err := p.Fetch(ctx, cep)
+			ch <- indexedResult{i, APIResult{Addr: addr, Source: p.Name(), Err: err, Latency: time.Since(start)}}
+		}(i, p)
+	}
+}
+
+type indexedResult struct {
+	index int
+	APIResult
+}
+
+// raceStrategy devolve a primeira resposta válida que chegar. Respostas com
+// erro são descartadas enquanto houver providers pendentes. Se várias
+// respostas válidas chegarem juntas, vence o provider que vem primeiro na
+// ordem informada.
+func raceStrategy(ctx context.Context, cep string, providers []Provider, _ StrategyOptions) []APIResult {
+	ch := make(chan indexedResult, len(providers))
+	launch(ctx, cep, providers, ch)
+
+	var errs []error
+	for pending := len(providers); pending > 0; pending-- {
+		select {
+		case res := <-ch:
+			if res.Err == nil {
+				return []APIResult{preferEarlier(res, ch)}
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", res.Source, res.Err))
+		case <-ctx.Done():
+			return []APIResult{{Err: ctxError(ctx)}}
+		}
+	}
+	return []APIResult{{Err: allFailed(errs)}}
+}
+
+// preferEarlier desempata a corrida: entre winner e as respostas válidas já
+// disponíveis em ch, devolve a do provider de menor índice.
+func preferEarlier(winner indexedResult, ch <-chan indexedResult) APIResult {
+	for {
+		select {
+		case res := <-ch:
+			if res.Err == nil && res.index < winner.index {
+				winner = res
+			}
+		default:
+			return winner.APIResult
+		}
+	}
+}
+
+// collect espera a resposta de todos os providers, na ordem em que foram
+// informados. Quem não responder até o fim de ctx fica com ctxError(ctx).
+func collect(ctx context.Context, cep string, providers []Provider) []APIResult {
+	ch := make(chan indexedResult, len(providers))
+	launch(ctx, cep, providers, ch)
+
+	results := make([]APIResult, len(providers))
+	done := make([]bool, len(providers))
+	for pending := len(providers); pending > 0; pending-- {
+		select {
+		case res := <-ch:
+			results[res.index] = res.APIResult
+			done[res.index] = true
+		case <-ctx.Done():
+			for i, p := range providers {
+				if !done[i] {
+					results[i] = APIResult{Source: p.Name(), Err: ctxError(ctx)}
+				}
+			}
+			return results
+		}
+	}
+	return results
+}
+
+// allStrategy devolve a resposta de todos os providers.
+func allStrategy(ctx context.Context, cep string, providers []Provider, _ StrategyOptions) []APIResult {
+	return collect(ctx, cep, providers)
+}
+
+// fallbackStrategy consulta um provider por vez, na ordem informada, até que
+// um deles responda sem erro. Cada tentativa tem prazo próprio (veja
+// StrategyOptions.StepTimeout), então só há uma conexão aberta por vez e um
+// provider travado não consome o prazo inteiro.
+func fallbackStrategy(ctx context.Context, cep string, providers []Provider, opts StrategyOptions) []APIResult {
+	res := APIResult{Err: ctxError(ctx)}
+	var errs []error
+	for i, p := range providers {
+		if ctx.Err() != nil {
+			break
+		}
+
+		step := opts.StepTimeout
+		if deadline, ok := ctx.Deadline(); ok && step <= 0 {
+			step = time.Until(deadline) / time.Duration(len(providers)-i)
+		}
+		stepCtx, cancel := ctx, context.CancelFunc(func() {})
+		if step > 0 {
+			stepCtx, cancel = context.WithTimeout(ctx, step)
+		}
+		start := time.Now()
+		addr, err := p.Fetch(stepCtx, cep)
+		cancel()
+
+		res = APIResult{Addr: addr, Source: p.Name(), Err: err, Latency: time.Since(start)}
+		if err == nil {
+			return []APIResult{res}
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+	}
+	if len(errs) > 0 {
+		res.Err = allFailed(errs)
+	}
+	return []APIResult{res}
+}
+
+// quorumStrategy espera todos os providers e só devolve um endereço se ao
+// menos opts.Quorum votos (ponderados por opts.Weights) concordarem em
+// cidade, estado e rua. Sem opts.Quorum, exige a maioria. Se o quórum não for
+// atingido, o erro traz o relatório das divergências; se nenhum provider
+// devolveu um endereço, é o de allFailed.
+func quorumStrategy(ctx context.Context, cep string, providers []Provider, opts StrategyOptions) []APIResult {
+	total := 0
+	for _, p := range providers {
+		total += opts.weight(p.Name())
+	}
+	need := opts.Quorum
+	if need <= 0 {
+		need = total/2 + 1
+	}
+
+	groups := map[string][]APIResult{}
+	votes := map[string]int{}
+	var order []string
+	var errs []error
+	for _, res := range collect(ctx, cep, providers) {
+		if res.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", res.Source, res.Err))
+			continue
+		}
+		key := agreementKey(res.Addr)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], res)
+		votes[key] += opts.weight(res.Source)
+	}
+
+	// Em caso de empate, vence o grupo do provider que vem primeiro
+	var best string
+	for _, key := range order {
+		if votes[key] > votes[best] {
+			best = key
+		}
+	}
+	if len(order) == 0 {
+		// Ninguém respondeu com um endereço: o erro diz se o CEP não existe
+		return []APIResult{{Err: allFailed(errs)}}
+	}
+	if votes[best] < need {
+		var report strings.Builder
+		report.WriteString(tr("quórum não atingido: %d de %d votos concordam (mínimo %d)", votes[best], total, need))
+		for _, key := range order {
+			a := groups[key][0].Addr
+			report.WriteString(tr("\n  %d voto(s) [%s]: %s, %s - %s",
+				votes[key], joinSources(groups[key]), a.Street, a.City, a.State))
+		}
+		for _, err := range errs {
+			report.WriteString(tr("\n  sem resposta: %v", err))
+		}
+		return []APIResult{{Err: &quorumError{report: report.String()}}}
+	}
+
+	return []APIResult{{Addr: groups[best][0].Addr, Source: joinSources(groups[best])}}
+}
+
+// quorumError é a falha do quórum quando os providers responderam mas não
+// concordaram o bastante. Para errors.Is, é ErrAllProvidersFailed.
+type quorumError struct {
+	report string
+}
+
+func (e *quorumError) Error() string { return e.report }
+
+func (e *quorumError) Unwrap() error { return ErrAllProvidersFailed }
+
+// joinSources junta os nomes dos providers de results com "+".
+func joinSources(results []APIResult) string {
+	sources := make([]string, len(results))
+	for i, res := range results {
+		sources[i] = res.Source
+	}
+	return strings.Join(sources, "+")
+}
+
+// agreementKey resume os campos usados para decidir se dois providers
+// concordam sobre um endereço.
+func agreementKey(a Address) string {
+	norm := func(s string) string { return strings.ToLower(strings.TrimSpace(s)) }
+	// "Av. Paulista" e "Avenida Paulista" são a mesma rua
+	return norm(a.City) + "|" + norm(a.State) + "|" + norm(expandStreetType(a.Street))
+}
+
+// mergeStrategy espera todos os providers e monta um único endereço,
+// preenchendo cada campo com o primeiro provider (na ordem informada) que o
+// tiver. A origem de cada campo fica em Provenance.
+func mergeStrategy(ctx context.Context, cep string, providers []Provider, _ StrategyOptions) []APIResult {
+	merged := APIResult{Provenance: map[string]string{}}
+	var sources []string
+	var errs []error
+	for _, res := range collect(ctx, cep, providers) {
+		if res.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", res.Source, res.Err))
+			continue
+		}
+		used := false
+		for _, name := range addressFieldNames {
+			dst := addressFields[name](&merged.Addr)
+			if src := *addressFields[name](&res.Addr); *dst == "" && src != "" {
+				*dst = src
+				merged.Provenance[name] = res.Source
+				used = true
+			}
+		}
+		if merged.Addr.Location == nil && res.Addr.Location != nil {
+			merged.Addr.Location = res.Addr.Location
+			merged.Provenance["location"] = res.Source
+			used = true
+		}
+		if used {
+			sources = append(sources, res.Source)
+		}
+	}
+
+	if len(sources) == 0 {
+		return []APIResult{{Err: allFailed(errs)}}
+	}
+	merged.Source = strings.Join(sources, "+")
+	return []APIResult{merged}
+}
+
+// hedgeStrategy aciona um provider por vez, na ordem informada. O próximo só
+// é acionado se o anterior falhar ou se nenhuma resposta válida chegar em
+// opts.HedgeDelay. Vence a primeira resposta válida.
+func hedgeStrategy(ctx context.Context, cep string, providers []Provider, opts StrategyOptions) []APIResult {
+	ch := make(chan indexedResult, len(providers))
+	next := 0
+	fire := func() {
+		launch(ctx, cep, providers[next:next+1], ch)
+		next++
+	}
+
+	timer := time.NewTimer(opts.HedgeDelay)
+	defer timer.Stop()
+	fire()
+
+	var errs []error
+	for pending := len(providers); pending > 0; {
+		select {
+		case res := <-ch:
+			pending--
+			if res.Err == nil {
+				return []APIResult{res.APIResult}
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", res.Source, res.Err))
+			if next < len(providers) {
+				fire()
+				timer.Reset(opts.HedgeDelay)
+			}
+		case <-timer.C:
+			if next < len(providers) {
+				fire()
+				timer.Reset(opts.HedgeDelay)
+			}
+		case <-ctx.Done():
+			return []APIResult{{Err: ctxError(ctx)}}
+		}
+	}
+	return []APIResult{{Err: allFailed(errs)}}
+}
+
+// preferStrategy dispara todos os providers como a corrida, mas usa a resposta
+// de opts.Prefer sempre que ela for válida e chegar em até opts.PreferGrace.
+// Depois desse prazo, ou se o preferido falhar, vence a primeira resposta
+// válida dos demais.
+func preferStrategy(ctx context.Context, cep string, providers []Provider, opts StrategyOptions) []APIResult {
+	ch := make(chan indexedResult, len(providers))
+	launch(ctx, cep, providers, ch)
+
+	grace := time.NewTimer(opts.PreferGrace)
+	defer grace.Stop()
+
+	var backup *APIResult
+	waiting := true // ainda aguardando o provider preferido
+	var errs []error
+	for pending := len(providers); pending > 0; {
+		select {
+		case res := <-ch:
+			pending--
+			preferred := strings.EqualFold(res.Source, opts.Prefer)
+			if res.Err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", res.Source, res.Err))
+				if preferred {
+					waiting = false
+				}
+			} else if preferred {
+				return []APIResult{res.APIResult}
+			} else if backup == nil {
+				backup = &res.APIResult
+			}
+			if backup != nil && !waiting {
+				return []APIResult{*backup}
+			}
+		case <-grace.C:
+			waiting = false
+			if backup != nil {
+				return []APIResult{*backup}
+			}
+		case <-ctx.Done():
+			return []APIResult{{Err: ctxError(ctx)}}
+		}
+	}
+	if backup != nil {
+		return []APIResult{*backup}
+	}
+	return []APIResult{{Err: allFailed(errs)}}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+	"time"
+)
+
+var (
+	addrSe       = Address{CEP: "01001000", Street: "Praça da Sé", City: "São Paulo", State: "SP"}
+	addrPaulista = Address{CEP: "01310100", Street: "Avenida Paulista", City: "São Paulo", State: "SP"}
+)
+
+func okProvider(name string, a Address) Provider   { return stubProvider{name: name, addr: a} }
+func failProvider(name string, err error) Provider { return stubProvider{name: name, err: err} }
+
+func TestQuorumStrategy(t *testing.T) {
+	tests := []struct {
+		name       string
+		providers  []Provider
+		opts       StrategyOptions
+		wantSource string
+		wantErr    error
+		wantExit   int
+	}{
+		{
+			name:       "maioria concorda",
+			providers:  []Provider{okProvider("A", addrSe), okProvider("B", addrSe), okProvider("C", addrPaulista)},
+			wantSource: "A+B",
+		},
+		{
+			name:       "Av. e Avenida concordam",
+			providers:  []Provider{okProvider("A", addrPaulista), okProvider("B", Address{Street: "Av. Paulista", City: "São Paulo", State: "SP"})},
+			wantSource: "A+B",
+		},
+		{
+			name:      "sem maioria",
+			providers: []Provider{okProvider("A", addrSe), okProvider("B", addrPaulista), failProvider("C", ErrNotFound)},
+			wantErr:   ErrAllProvidersFailed,
+			wantExit:  exitProvidersError,
+		},
+		{
+			name:       "peso decide",
+			providers:  []Provider{okProvider("A", addrSe), okProvider("B", addrPaulista), okProvider("C", addrPaulista)},
+			opts:       StrategyOptions{Weights: map[string]int{"A": 3}},
+			wantSource: "A",
+		},
+		{
+			name:      "quórum explícito não atingido",
+			providers: []Provider{okProvider("A", addrSe), okProvider("B", addrSe), okProvider("C", addrPaulista)},
+			opts:      StrategyOptions{Quorum: 3},
+			wantErr:   ErrAllProvidersFailed,
+			wantExit:  exitProvidersError,
+		},
+		{
+			name:      "todos dizem que não existe",
+			providers: []Provider{failProvider("A", ErrNotFound), failProvider("B", ErrNotFound), failProvider("C", ErrNotFound)},
+			wantErr:   ErrNotFound,
+			wantExit:  exitNotFound,
+		},
+		{
+			name:      "todos falham",
+			providers: []Provider{failProvider("A", ErrNotFound), failProvider("B", &HTTPStatusError{Code: 500}), failProvider("C", ErrNotFound)},
+			wantErr:   ErrAllProvidersFailed,
+			wantExit:  exitProvidersError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := quorumStrategy(context.Background(), "01001000", tt.providers, tt.opts)
+			if len(results) != 1 {
+				t.Fatalf("%d resultados, quero 1", len(results))
+			}
+			res := results[0]
+			if tt.wantErr != nil {
+				if !errors.Is(res.Err, tt.wantErr) {
+					t.Fatalf("err = %v, quero %v", res.Err, tt.wantErr)
+				}
+				if got := exitCode(res.Err); got != tt.wantExit {
+					t.Errorf("código de saída %d, quero %d", got, tt.wantExit)
+				}
+				return
+			}
+			if res.Err != nil {
+				t.Fatalf("err = %v", res.Err)
+			}
+			if res.Source != tt.wantSource {
+				t.Errorf("origem %q, quero %q", res.Source, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestQuorumStrategyTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	providers := []Provider{stubProvider{name: "A", addr: addrSe, delay: time.Hour}, okProvider("B", addrSe)}
+	res := quorumStrategy(ctx, "01001000", providers, StrategyOptions{Quorum: 2})[0]
+	if !errors.Is(res.Err, ErrAllProvidersFailed) {
+		t.Fatalf("err = %v", res.Err)
+	}
+}
+
+func TestMergeStrategy(t *testing.T) {
+	loc := &Location{Latitude: -23.55, Longitude: -46.63}
+	tests := []struct {
+		name           string
+		providers      []Provider
+		want           Address
+		wantSource     string
+		wantProvenance map[string]string
+		wantErr        error
+	}{
+		{
+			name: "completa os campos pela ordem",
+			providers: []Provider{
+				okProvider("A", Address{CEP: "01001000", Street: "Praça da Sé", City: "São Paulo"}),
+				okProvider("B", Address{CEP: "01001000", Street: "Pça. da Sé", Neighborhood: "Sé", State: "SP"}),
+				okProvider("C", Address{Location: loc}),
+			},
+			want:       Address{CEP: "01001000", Street: "Praça da Sé", Neighborhood: "Sé", City: "São Paulo", State: "SP", Location: loc},
+			wantSource: "A+B+C",
+			wantProvenance: map[string]string{
+				"cep": "A", "street": "A", "city": "A", "neighborhood": "B", "state": "B", "location": "C",
+			},
+		},
+		{
+			name: "quem não acrescenta nada fica de fora",
+			providers: []Provider{
+				okProvider("A", addrSe),
+				okProvider("B", Address{City: "Sampa"}),
+				failProvider("C", ErrNotFound),
+			},
+			want:           addrSe,
+			wantSource:     "A",
+			wantProvenance: map[string]string{"cep": "A", "street": "A", "city": "A", "state": "A"},
+		},
+		{
+			name:      "todos dizem que não existe",
+			providers: []Provider{failProvider("A", ErrNotFound), failProvider("B", ErrNotFound)},
+			wantErr:   ErrNotFound,
+		},
+		{
+			name:      "todos falham",
+			providers: []Provider{failProvider("A", ErrNotFound), failProvider("B", &HTTPStatusError{Code: 502})},
+			wantErr:   ErrAllProvidersFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := mergeStrategy(context.Background(), "01001000", tt.providers, StrategyOptions{})[0]
+			if tt.wantErr != nil {
+				if !errors.Is(res.Err, tt.wantErr) {
+					t.Fatalf("err = %v, quero %v", res.Err, tt.wantErr)
+				}
+				return
+			}
+			if res.Err != nil {
+				t.Fatalf("err = %v", res.Err)
+			}
+			if res.Addr != tt.want {
+				t.Errorf("endereço = %+v, quero %+v", res.Addr, tt.want)
+			}
+			if res.Source != tt.wantSource {
+				t.Errorf("origem %q, quero %q", res.Source, tt.wantSource)
+			}
+			if !maps.Equal(res.Provenance, tt.wantProvenance) {
+				t.Errorf("procedência = %v, quero %v", res.Provenance, tt.wantProvenance)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"strings"
+)
+
+// streetType é um tipo de logradouro: o nome por extenso, a abreviação
+// usada por --street-type abbrev e as outras grafias encontradas nos
+// providers.
+type streetType struct {
+	name, abbrev string
+	variants     []string
+}
+
+var streetTypes = []streetType{
+	{"Avenida", "Av.", []string{"Av", "Ave"}},
+	{"Rua", "R.", []string{"R"}},
+	{"Alameda", "Al.", []string{"Al"}},
+	{"Praça", "Pça.", []string{"Pça", "Pç.", "Pc.", "Pca", "Praca"}},
+	{"Travessa", "Tv.", []string{"Tv", "Trav.", "Trav", "Tr."}},
+	{"Rodovia", "Rod.", []string{"Rod"}},
+	{"Estrada", "Estr.", []string{"Estr", "Est."}},
+	{"Largo", "Lgo.", []string{"Lgo", "Lg."}},
+	{"Parque", "Pq.", []string{"Pq", "Pqe."}},
+	{"Viaduto", "Vd.", []string{"Vd", "Vdt."}},
+	{"Ladeira", "Ld.", []string{"Ld", "Lad."}},
+	{"Beco", "Bc.", []string{"Bc"}},
+	{"Vila", "Vl.", []string{"Vl"}},
+	{"Quadra", "Qd.", []string{"Qd", "Q."}},
+	{"Conjunto", "Cj.", []string{"Cj", "Conj."}},
+}
+
+// findStreetType identifica o tipo de logradouro no começo de street,
+// devolvendo-o com o resto do nome.
+func findStreetType(street string) (streetType, string, bool) {
+	first, rest, _ := strings.Cut(strings.TrimSpace(street), " ")
+	for _, t := range streetTypes {
+		for _, s := range append([]string{t.name, t.abbrev}, t.variants...) {
+			if strings.EqualFold(first, s) {
+				return t, rest, true
+			}
+		}
+	}
+	return streetType{}, "", false
+}
+
+// expandStreetType escreve por extenso o tipo de logradouro de street
+// ("Av. Paulista" vira "Avenida Paulista").
+func expandStreetType(street string) string {
+	t, rest, ok := findStreetType(street)
+	if !ok || rest == "" {
+		return street
+	}
+	return t.name + " " + rest
+}
+
+// abbrevStreetType abrevia o tipo de logradouro de street ("Avenida
+// Paulista" vira "Av. Paulista").
+func abbrevStreetType(street string) string {
+	t, rest, ok := findStreetType(street)
+	if !ok || rest == "" {
+		return street
+	}
+	return t.abbrev + " " + rest
+}
+
+// streetTypeTransform devolve a transformação de --street-type.
+func streetTypeTransform(mode string) (func(Address) Address, error) {
+	var f func(string) string
+	switch mode {
+	case "expand":
+		f = expandStreetType
+	case "abbrev":
+		f = abbrevStreetType
+	default:
+		return nil, errors.New(tr("--street-type desconhecido: %s (use expand ou abbrev)", mode))
+	}
+	return func(a Address) Address {
+		a.Street = f(a.Street)
+		return a
+	}, nil
+}
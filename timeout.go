@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type timeoutProvider struct {
+	Provider
+	timeout time.Duration
+}
+
+// withTimeout limita cada consulta a p a timeout, dentro do prazo geral.
+func withTimeout(p Provider, timeout time.Duration) Provider {
+	if timeout <= 0 {
+		return p
+	}
+	return timeoutProvider{Provider: p, timeout: timeout}
+}
+
+func (t timeoutProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	pctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	addr, err := t.Provider.Fetch(pctx, cep)
+	// O prazo próprio do provider acabou, mas não o da consulta
+	if err != nil && ctx.Err() == nil && errors.Is(pctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return addr, err
+}
+
+// parseProviderTimeouts lê uma lista no formato "BrasilAPI=700ms,ViaCEP=1s".
+func parseProviderTimeouts(s string) (map[string]time.Duration, error) {
+	timeouts := map[string]time.Duration{}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, errors.New(tr("timeout por provider inválido: %q (use Nome=duração)", item))
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", tr("timeout da %s", name), err)
+		}
+		timeouts[strings.ToLower(strings.TrimSpace(name))] = d
+	}
+	return timeouts, nil
+}
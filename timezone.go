@@ -0,0 +1,79 @@
+package main
+
+import "strings"
+
+// stateTimezones é o fuso horário IANA de cada UF. Estados com mais de um
+// fuso têm as exceções em cityTimezones.
+var stateTimezones = map[string]string{
+	"AC": "America/Rio_Branco",
+	"AL": "America/Maceio",
+	"AM": "America/Manaus",
+	"AP": "America/Belem",
+	"BA": "America/Bahia",
+	"CE": "America/Fortaleza",
+	"DF": "America/Sao_Paulo",
+	"ES": "America/Sao_Paulo",
+	"GO": "America/Sao_Paulo",
+	"MA": "America/Fortaleza",
+	"MG": "America/Sao_Paulo",
+	"MS": "America/Campo_Grande",
+	"MT": "America/Cuiaba",
+	"PA": "America/Belem",
+	"PB": "America/Fortaleza",
+	"PE": "America/Recife",
+	"PI": "America/Fortaleza",
+	"PR": "America/Sao_Paulo",
+	"RJ": "America/Sao_Paulo",
+	"RN": "America/Fortaleza",
+	"RO": "America/Porto_Velho",
+	"RR": "America/Boa_Vista",
+	"RS": "America/Sao_Paulo",
+	"SC": "America/Sao_Paulo",
+	"SE": "America/Maceio",
+	"SP": "America/Sao_Paulo",
+	"TO": "America/Araguaina",
+}
+
+// cityTimezones são os municípios cujo fuso difere do da UF, pela chave
+// "uf/município" com o nome na forma de matchKey (minúsculas, sem acentos),
+// para que "Santarem" ou "SANTARÉM" também sejam achados.
+var cityTimezones = map[string]string{
+	"pe/fernando de noronha": "America/Noronha",
+
+	// Oeste do Amazonas
+	"am/atalaia do norte":  "America/Eirunepe",
+	"am/benjamin constant": "America/Eirunepe",
+	"am/boca do acre":      "America/Eirunepe",
+	"am/eirunepe":          "America/Eirunepe",
+	"am/envira":            "America/Eirunepe",
+	"am/guajara":           "America/Eirunepe",
+	"am/ipixuna":           "America/Eirunepe",
+	"am/itamarati":         "America/Eirunepe",
+	"am/pauini":            "America/Eirunepe",
+
+	// Oeste do Pará
+	"pa/alenquer":       "America/Santarem",
+	"pa/itaituba":       "America/Santarem",
+	"pa/juruti":         "America/Santarem",
+	"pa/monte alegre":   "America/Santarem",
+	"pa/obidos":         "America/Santarem",
+	"pa/oriximina":      "America/Santarem",
+	"pa/santarem":       "America/Santarem",
+	"pa/terra santa":    "America/Santarem",
+	"pa/trairao":        "America/Santarem",
+	"pa/novo progresso": "America/Santarem",
+}
+
+// timezoneFor deriva o fuso horário IANA de um endereço brasileiro pela UF
+// e, nos estados com mais de um fuso, pelo município. Fora do Brasil, ou com
+// UF desconhecida, devolve "".
+func timezoneFor(a Address) string {
+	if a.Country != "" && !strings.EqualFold(a.Country, "BR") {
+		return ""
+	}
+	state := strings.ToUpper(a.State)
+	if tz, ok := cityTimezones[strings.ToLower(state)+"/"+matchKey(a.City)]; ok {
+		return tz
+	}
+	return stateTimezones[state]
+}
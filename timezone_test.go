@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestTimezoneFor(t *testing.T) {
+	tests := []struct {
+		addr Address
+		want string
+	}{
+		{Address{State: "SP", City: "São Paulo"}, "America/Sao_Paulo"},
+		{Address{State: "PA", City: "Belém"}, "America/Belem"},
+		{Address{State: "PA", City: "Santarém"}, "America/Santarem"},
+		{Address{State: "PA", City: "Santarem"}, "America/Santarem"},
+		{Address{State: "pa", City: "SANTARÉM"}, "America/Santarem"},
+		{Address{State: "PA", City: "Obidos"}, "America/Santarem"},
+		{Address{State: "AM", City: "Eirunepe"}, "America/Eirunepe"},
+		{Address{State: "AM", City: "Manaus"}, "America/Manaus"},
+		{Address{State: "PE", City: "Fernando de Noronha"}, "America/Noronha"},
+		{Address{State: "XX", City: "Santarém"}, ""},
+		{Address{State: "SP", City: "São Paulo", Country: "US"}, ""},
+	}
+	for _, tt := range tests {
+		if got := timezoneFor(tt.addr); got != tt.want {
+			t.Errorf("timezoneFor(%s/%s) = %q, quero %q", tt.addr.State, tt.addr.City, got, tt.want)
+		}
+	}
+}
+
+// Com --normalize ascii, os transforms tiram os acentos antes da saída.
+func TestTimezoneForNormalizedCity(t *testing.T) {
+	ascii, err := normalizeTransform("ascii")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := timezoneFor(ascii(Address{State: "PA", City: "Santarém"})); got != "America/Santarem" {
+		t.Errorf("fuso = %q, quero America/Santarem", got)
+	}
+}
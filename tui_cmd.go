@@ -0,0 +1,352 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// runTUI abre a interface de tela cheia: uma caixa de busca, a latência de
+// cada provider na consulta atual e o endereço, com campos copiáveis.
+func runTUI(args []string) int {
+	fs := flag.NewFlagSet("cep tui", flag.ExitOnError)
+	opts := registerOptions(fs)
+	parseFlags(fs, args)
+
+	app, err := opts.setup()
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	defer app.Close()
+
+	events := make(chan providerEvent, 64)
+	var names []string
+	for i, p := range app.resolver.Providers {
+		names = append(names, p.Name())
+		app.resolver.Providers[i] = observedProvider{Provider: p, events: events}
+	}
+
+	input := textinput.New()
+	input.Placeholder = "01310100"
+	input.CharLimit = 16
+	input.Focus()
+
+	m := tuiModel{
+		app:       app,
+		input:     input,
+		events:    events,
+		providers: names,
+		status:    map[string]providerStatus{},
+		last:      map[string]time.Duration{},
+	}
+	if _, err := tea.NewProgram(m, tea.WithAltScreen()).Run(); err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	return exitOK
+}
+
+// providerEvent informa o início ou o fim de uma consulta a um provider.
+type providerEvent struct {
+	cep     string
+	name    string
+	done    bool
+	elapsed time.Duration
+	err     error
+}
+
+// observedProvider envia para events o início e o fim de cada consulta a
+// Provider.
+type observedProvider struct {
+	Provider
+	events chan<- providerEvent
+}
+
+func (o observedProvider) Fetch(ctx context.Context, cep string) (Address, error) {
+	o.send(providerEvent{cep: cep, name: o.Name()})
+	start := time.Now()
+	addr, err := o.Provider.Fetch(ctx, cep)
+	o.send(providerEvent{cep: cep, name: o.Name(), done: true, elapsed: time.Since(start), err: err})
+	return addr, err
+}
+
+// send descarta o evento se a interface não estiver acompanhando, em vez de
+// atrasar a consulta.
+func (o observedProvider) send(e providerEvent) {
+	select {
+	case o.events <- e:
+	default:
+	}
+}
+
+type providerStatus struct {
+	start   time.Time
+	done    bool
+	elapsed time.Duration
+	err     error
+}
+
+type lookupDoneMsg struct {
+	cep    string
+	res    *APIResult
+	errMsg string
+}
+
+type tickMsg time.Time
+
+type copiedMsg string
+
+type tuiField struct {
+	label string
+	value string
+}
+
+type tuiModel struct {
+	app    *app
+	input  textinput.Model
+	events chan providerEvent
+
+	providers []string
+	// status é o andamento de cada provider na consulta a cep; last guarda a
+	// latência da última resposta de cada um, de qualquer consulta.
+	status map[string]providerStatus
+	last   map[string]time.Duration
+
+	cep     string
+	busy    bool
+	source  string
+	fields  []tuiField
+	message string
+
+	// normalized é cep normalizado pelo Resolver, como vem nos
+	// providerEvent.
+	normalized string
+
+	// Com focusResults, as setas escolhem o campo selected do resultado.
+	focusResults bool
+	selected     int
+}
+
+func (m tuiModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, m.waitEvent())
+}
+
+func (m tuiModel) waitEvent() tea.Cmd {
+	return func() tea.Msg { return <-m.events }
+}
+
+func tick() tea.Cmd {
+	return tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+// resolve consulta cep e devolve o resultado já enriquecido, ou a mesma
+// mensagem de erro do modo texto.
+func (m tuiModel) resolve(cep string) tea.Cmd {
+	resolver := m.app.resolver
+	return func() tea.Msg {
+		lookup := resolver.Resolve(context.Background(), cep)
+		var res *APIResult
+		capture := func(_ io.Writer, r APIResult) error {
+			if res == nil {
+				res = &r
+			}
+			return nil
+		}
+		var errs bytes.Buffer
+		printLookup(io.Discard, &errs, resolver, cep, lookup, capture)
+		return lookupDoneMsg{cep: cep, res: res, errMsg: strings.TrimSpace(errs.String())}
+	}
+}
+
+func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc:
+			return m, tea.Quit
+		case tea.KeyTab:
+			m.focusResults = !m.focusResults && len(m.fields) > 0
+			if m.focusResults {
+				m.input.Blur()
+			} else {
+				m.input.Focus()
+			}
+			return m, nil
+		}
+		if m.focusResults {
+			return m.updateResults(msg)
+		}
+		if msg.Type == tea.KeyEnter {
+			cep := strings.TrimSpace(m.input.Value())
+			if cep == "" || m.busy {
+				return m, nil
+			}
+			m.cep, m.busy, m.message = cep, true, ""
+			m.normalized = cep
+			if normalize := m.app.resolver.Normalize; normalize != nil {
+				m.normalized = normalize(cep)
+			}
+			m.source, m.fields, m.selected = "", nil, 0
+			m.status = map[string]providerStatus{}
+			return m, tea.Batch(m.resolve(cep), tick())
+		}
+
+	case providerEvent:
+		if msg.cep == m.normalized {
+			if msg.done {
+				m.status[msg.name] = providerStatus{done: true, elapsed: msg.elapsed, err: msg.err}
+				m.last[msg.name] = msg.elapsed
+			} else {
+				m.status[msg.name] = providerStatus{start: time.Now()}
+			}
+		}
+		return m, m.waitEvent()
+
+	case tickMsg:
+		if m.busy {
+			return m, tick()
+		}
+		return m, nil
+
+	case lookupDoneMsg:
+		if msg.cep != m.cep {
+			return m, nil
+		}
+		m.busy = false
+		if msg.res == nil {
+			m.message = msg.errMsg
+			return m, nil
+		}
+		m.source = msg.res.Source
+		m.fields = resultFields(*msg.res)
+		return m, nil
+
+	case copiedMsg:
+		m.message = tr("%s copiado", string(msg))
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m tuiModel) updateResults(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		m.selected = max(m.selected-1, 0)
+	case "down", "j":
+		m.selected = min(m.selected+1, len(m.fields)-1)
+	case "enter", "c":
+		f := m.fields[m.selected]
+		return m, copyToClipboard(f.label, f.value)
+	case "a":
+		var lines []string
+		for _, f := range m.fields {
+			lines = append(lines, f.label+": "+f.value)
+		}
+		return m, copyToClipboard(tr("Endereço"), strings.Join(lines, "\n"))
+	}
+	return m, nil
+}
+
+// copyToClipboard copia value para a área de transferência do terminal com
+// a sequência OSC 52, que funciona inclusive via SSH.
+func copyToClipboard(label, value string) tea.Cmd {
+	return func() tea.Msg {
+		fmt.Fprintf(os.Stdout, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(value)))
+		return copiedMsg(label)
+	}
+}
+
+// resultFields lista os campos preenchidos de res, na ordem de exibição.
+func resultFields(res APIResult) []tuiField {
+	var fields []tuiField
+	for _, name := range addressFieldNames {
+		if v := *addressFields[name](&res.Addr); v != "" {
+			fields = append(fields, tuiField{tr(fieldLabels[name]), v})
+		}
+	}
+	if loc := res.Addr.Location; loc != nil {
+		fields = append(fields,
+			tuiField{"Latitude", fmt.Sprintf("%f", loc.Latitude)},
+			tuiField{"Longitude", fmt.Sprintf("%f", loc.Longitude)},
+		)
+	}
+	return fields
+}
+
+var (
+	tuiTitle    = lipgloss.NewStyle().Bold(true)
+	tuiBox      = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	tuiOK       = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	tuiFail     = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	tuiFaint    = lipgloss.NewStyle().Faint(true)
+	tuiSelected = lipgloss.NewStyle().Reverse(true)
+)
+
+func (m tuiModel) View() string {
+	var b strings.Builder
+	b.WriteString(tuiBox.Render(tuiTitle.Render("CEP ") + m.input.View()))
+	b.WriteString("\n\n")
+
+	var lines []string
+	for _, name := range m.providers {
+		st, ok := m.status[name]
+		var state string
+		switch {
+		case !ok:
+			state = tuiFaint.Render("-")
+		case !st.done:
+			state = fmt.Sprintf("… %s", time.Since(st.start).Round(10*time.Millisecond))
+		case errors.Is(st.err, context.Canceled):
+			// Na corrida, os perdedores são cancelados quando alguém vence
+			state = tuiFaint.Render(tr("cancelado após %s", st.elapsed.Round(time.Millisecond)))
+		case st.err != nil:
+			state = tuiFail.Render(fmt.Sprintf("✗ %s  %v", st.elapsed.Round(time.Millisecond), st.err))
+		default:
+			state = tuiOK.Render(fmt.Sprintf("✓ %s", st.elapsed.Round(time.Millisecond)))
+		}
+		if name == m.source {
+			state += tuiTitle.Render(tr("  vencedor"))
+		}
+		last := ""
+		if d, ok := m.last[name]; ok {
+			last = tuiFaint.Render(tr("  (última: %s)", d.Round(time.Millisecond)))
+		}
+		lines = append(lines, fmt.Sprintf("%-12s %s%s", name, state, last))
+	}
+	b.WriteString(tuiBox.Render(tuiTitle.Render("Providers") + "\n" + strings.Join(lines, "\n")))
+	b.WriteString("\n\n")
+
+	if len(m.fields) > 0 {
+		lines = lines[:0]
+		for i, f := range m.fields {
+			line := fmt.Sprintf("%-11s %s", f.label, f.value)
+			if m.focusResults && i == m.selected {
+				line = tuiSelected.Render(line)
+			}
+			lines = append(lines, line)
+		}
+		title := tr("Resultado (%s)", m.source)
+		b.WriteString(tuiBox.Render(tuiTitle.Render(title) + "\n" + strings.Join(lines, "\n")))
+		b.WriteString("\n\n")
+	}
+	if m.message != "" {
+		b.WriteString(m.message + "\n\n")
+	}
+	b.WriteString(tuiFaint.Render(tr("Enter consulta · Tab alterna entre busca e resultado · ↑/↓ escolhe · Enter/c copia o campo · a copia tudo · Esc sai")))
+	return b.String()
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Os eventos vêm com o CEP normalizado, mesmo que ele tenha sido digitado
+// com hífen.
+func TestTUIProviderEventsForFormattedCEP(t *testing.T) {
+	input := textinput.New()
+	input.SetValue("01310-100")
+	var m tea.Model = tuiModel{
+		app:    &app{resolver: &Resolver{Normalize: normalizeCEP, Validate: validateCEP}},
+		input:  input,
+		events: make(chan providerEvent),
+		status: map[string]providerStatus{},
+		last:   map[string]time.Duration{},
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = m.Update(providerEvent{cep: "01310100", name: "ViaCEP", done: true, elapsed: 42 * time.Millisecond})
+
+	got := m.(tuiModel)
+	if st := got.status["ViaCEP"]; !st.done || st.elapsed != 42*time.Millisecond {
+		t.Errorf("status do ViaCEP = %+v", st)
+	}
+	if got.last["ViaCEP"] != 42*time.Millisecond {
+		t.Errorf("última latência do ViaCEP = %v", got.last["ViaCEP"])
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// validateFormats são os formatos aceitos pelo subcomando validate.
+var validateFormats = []string{"text", "json", "ndjson", "yaml", "xml"}
+
+// validateFlags são as opções do subcomando validate.
+type validateFlags struct {
+	opts     *options
+	cep      string
+	want     Address
+	minScore float64
+	format   string
+}
+
+func registerValidateFlags(fs *flag.FlagSet) *validateFlags {
+	f := &validateFlags{opts: registerOptions(fs)}
+	fs.StringVar(&f.cep, "cep", "", "CEP a validar")
+	fs.StringVar(&f.want.Street, "street", "", "logradouro informado, ex.: \"Av Paulista\"")
+	fs.StringVar(&f.want.Neighborhood, "neighborhood", "", "bairro informado")
+	fs.StringVar(&f.want.City, "city", "", "cidade informada")
+	fs.StringVar(&f.want.State, "state", "", "UF ou nome do estado informado")
+	fs.Float64Var(&f.minScore, "min-score", 0.8, "nota mínima, de 0 a 1, para o endereço conferir")
+	fs.StringVar(&f.format, "format", "text", "formato da saída: "+strings.Join(validateFormats, ", "))
+	return f
+}
+
+// runValidate consulta o CEP e confere se os campos informados batem com o
+// endereço encontrado, sem diferenciar acentos e tolerando erros de
+// digitação. Sai com exitMismatch se a nota ficar abaixo de --min-score.
+func runValidate(args []string) int {
+	fs := flag.NewFlagSet("cep validate", flag.ExitOnError)
+	f := registerValidateFlags(fs)
+	parseFlags(fs, args)
+
+	if fs.NArg() > 0 || f.cep == "" || (f.want == Address{}) {
+		fmt.Println(tr("Uso: go run main.go validate [opções] --cep 01310100 [--street \"Av Paulista\"] [--city \"Sao Paulo\"] ..."))
+		return exitUsage
+	}
+	if !slices.Contains(validateFormats, f.format) {
+		fmt.Println(tr("formato desconhecido: %s (use %s)", f.format, strings.Join(validateFormats, ", ")))
+		return exitUsage
+	}
+	if f.minScore < 0 || f.minScore > 1 {
+		fmt.Println(tr("--min-score deve estar entre 0 e 1"))
+		return exitUsage
+	}
+	if !checkCEPs(os.Stdout, f.opts.country, []string{f.cep}) {
+		return exitInvalidCEP
+	}
+
+	app, err := f.opts.setup()
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	defer app.Close()
+
+	lookup := app.resolver.Resolve(context.Background(), f.cep)
+	var found *APIResult
+	// Só as mensagens de erro; o endereço sai junto com a comparação
+	printLookup(io.Discard, os.Stdout, app.resolver, f.cep, lookup, func(_ io.Writer, res APIResult) error {
+		if found == nil {
+			found = &res
+		}
+		return nil
+	})
+	if found == nil {
+		return lookupExitCode(lookup)
+	}
+
+	matches, score := matchFields(f.want, found.Addr)
+	for i := range matches {
+		matches[i].Score = round2(matches[i].Score)
+	}
+	v := validateResult{
+		Pass:    score >= f.minScore,
+		Score:   round2(score),
+		Fields:  matches,
+		Address: newJSONResult(*found),
+	}
+	if err := v.write(os.Stdout, f.format); err != nil {
+		fmt.Fprint(os.Stderr, tr("Erro ao escrever a saída: %v\n", err))
+		return exitUsage
+	}
+	if !v.Pass {
+		return exitMismatch
+	}
+	return exitOK
+}
+
+func round2(x float64) float64 {
+	return math.Round(x*100) / 100
+}
+
+// validateResult é a saída do subcomando validate.
+type validateResult struct {
+	Pass    bool         `json:"pass"`
+	Score   float64      `json:"score"`
+	Fields  []fieldMatch `json:"fields"`
+	Address jsonResult   `json:"address"`
+}
+
+func (v validateResult) write(w io.Writer, format string) error {
+	switch format {
+	case "text":
+		for _, m := range v.Fields {
+			fmt.Fprintf(w, "%s %s → %s %s\n", paint(w, colorLabel, tr(fieldLabels[m.Field])+":"), m.Input, m.Found, percent(m.Score))
+		}
+		verdict := paint(w, colorWinner, tr("confere"))
+		if !v.Pass {
+			verdict = paint(w, colorError, tr("não confere"))
+		}
+		_, err := fmt.Fprintln(w, paint(w, colorLabel, tr("Resultado:")), verdict, percent(v.Score))
+		return err
+	case "json", "ndjson":
+		enc := json.NewEncoder(w)
+		if format == "json" {
+			enc.SetIndent("", "  ")
+		}
+		return enc.Encode(v)
+	case "yaml", "xml":
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+		if format == "xml" {
+			return writeXMLNode(w, "validation", doc.Content[0])
+		}
+		return writeYAMLNode(w, &doc)
+	}
+	return errors.New(tr("formato desconhecido: %s (use %s)", format, strings.Join(validateFormats, ", ")))
+}
+
+// percent escreve uma nota de 0 a 1 como porcentagem, entre parênteses.
+func percent(score float64) string {
+	return fmt.Sprintf("(%.0f%%)", score*100)
+}
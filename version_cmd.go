@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Metadados do build, injetados com
+//
+//	go build -ldflags "-X main.version=1.2.0 -X main.commit=$(git rev-parse --short HEAD) -X main.date=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Sem eles, commit e data vêm das informações de VCS que o go build grava.
+var (
+	version = "dev"
+	commit  = ""
+	date    = ""
+)
+
+func runVersion([]string) int {
+	c, d, modified := commit, date, false
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if c == "" {
+					c = s.Value
+					if len(c) > 12 {
+						c = c[:12]
+					}
+				}
+			case "vcs.time":
+				if d == "" {
+					d = s.Value
+				}
+			case "vcs.modified":
+				modified = s.Value == "true"
+			}
+		}
+	}
+	if c == "" {
+		c = tr("desconhecido")
+	} else if modified && commit == "" {
+		c += "-dirty"
+	}
+	if d == "" {
+		d = tr("desconhecida")
+	}
+
+	fmt.Printf("cep %s\n", version)
+	fmt.Print(tr("commit: %s\ndata do build: %s\n", c, d))
+	fmt.Printf("go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	return exitOK
+}
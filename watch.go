@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// watchLoop repete a consulta de ceps a cada --watch, até o processo ser
+// interrompido, e só escreve quando o endereço resolvido muda ou quando um
+// provider passa a falhar (ou volta a responder).
+func (f *lookupFlags) watchLoop(a *app, ceps []string, write formatter) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Acompanha o resultado de cada provider, inclusive os que perdem a
+	// corrida, que o Lookup não traz
+	events := make(chan providerEvent, 64)
+	for i, p := range a.resolver.Providers {
+		a.resolver.Providers[i] = observedProvider{Provider: p, events: events}
+	}
+	var (
+		mu       sync.Mutex
+		failures = map[string]error{}
+	)
+	go func() {
+		for e := range events {
+			if !e.done || errors.Is(e.err, context.Canceled) {
+				continue
+			}
+			mu.Lock()
+			failures[e.name] = e.err
+			mu.Unlock()
+		}
+	}()
+
+	last := map[string]string{}
+	reported := map[string]error{}
+	ticker := time.NewTicker(f.watch)
+	defer ticker.Stop()
+	for {
+		lookups := a.resolver.ResolveAll(ctx, ceps, f.concurrency)
+		if ctx.Err() != nil {
+			return exitOK
+		}
+		now := time.Now().Format("15:04:05")
+		for i, lookup := range lookups {
+			var out bytes.Buffer
+			printLookup(&out, &out, a.resolver, ceps[i], lookup, write)
+			key := watchKey(lookup, out.String())
+			if key == last[ceps[i]] {
+				continue
+			}
+			last[ceps[i]] = key
+			fmt.Printf("[%s] %s\n%s\n", now, ceps[i], out.String())
+		}
+
+		mu.Lock()
+		for _, p := range a.resolver.Providers {
+			name := p.Name()
+			was, is := reported[name], failures[name]
+			switch {
+			case is != nil && was == nil:
+				fmt.Print(tr("[%s] %s passou a falhar: %v\n", now, name, is))
+			case is == nil && was != nil:
+				fmt.Print(tr("[%s] %s voltou a responder\n", now, name))
+			}
+			reported[name] = is
+		}
+		mu.Unlock()
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return exitOK
+		}
+	}
+}
+
+// watchKey identifica o desfecho de uma consulta: o endereço, se houve um,
+// ou a mensagem de erro. Um provider diferente vencer a corrida com o mesmo
+// endereço não conta como mudança.
+func watchKey(lookup Lookup, output string) string {
+	for _, res := range lookup.Results {
+		if res.Err == nil {
+			data, _ := json.Marshal(res.Addr)
+			return string(data)
+		}
+	}
+	return output
+}